@@ -0,0 +1,114 @@
+/*
+Copyright © 2023 API7.ai
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+// newDefaultsCmd represents the defaults command
+func newDefaultsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "defaults",
+		Short: "Manage the default values of plugins",
+		Long:  `Manages the default values of plugins, which are filled in before comparing the plugin configurations.`,
+	}
+
+	cmd.AddCommand(newDefaultsGenerateCmd())
+	return cmd
+}
+
+// newDefaultsGenerateCmd represents the defaults generate command
+func newDefaultsGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate the default values of plugins from their schemas",
+		Long: `Generates the default values of plugins from the JSON schemas of plugins enabled on APISIX,
+or from the schema files (<plugin name>.json) in --schema-dir, and writes them to the output file.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schemaDir, err := cmd.Flags().GetString("schema-dir")
+			if err != nil {
+				color.Red("Failed to get the schema directory: %v", err)
+				return err
+			}
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				color.Red("Failed to get the output file: %v", err)
+				return err
+			}
+
+			var schemas map[string]map[string]interface{}
+			if schemaDir != "" {
+				schemas, err = types.ReadPluginSchemas(schemaDir)
+				if err != nil {
+					color.Red("Failed to read the plugin schemas: %v", err)
+					return err
+				}
+			} else {
+				err = checkConfig()
+				if err != nil {
+					return err
+				}
+				schemas, err = getPluginSchemas()
+				if err != nil {
+					return err
+				}
+			}
+
+			defaults := types.GenerateDefaultValues(schemas)
+			content, err := json.MarshalIndent(defaults, "", "  ")
+			if err != nil {
+				color.Red("Failed to marshal the default values: %v", err)
+				return err
+			}
+			err = os.WriteFile(output, append(content, '\n'), 0644)
+			if err != nil {
+				color.Red("Failed to write the default values: %v", err)
+				return err
+			}
+
+			color.Green("Generated the default values of %d plugins to %s", len(defaults), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("schema-dir", "", "read the plugin schemas from the directory instead of APISIX")
+	cmd.Flags().StringP("output", "o", "default_values.json", "output file of the default values")
+	return cmd
+}
+
+// getPluginSchemas returns the JSON schemas of plugins enabled on APISIX.
+func getPluginSchemas() (map[string]map[string]interface{}, error) {
+	plugins, err := rootConfig.APISIXCluster.Plugin().List(context.Background())
+	if err != nil {
+		color.Red("Failed to list plugins: %v", err)
+		return nil, err
+	}
+
+	schemas := make(map[string]map[string]interface{}, len(plugins))
+	for _, name := range plugins {
+		content, err := rootConfig.APISIXCluster.Plugin().Schema(context.Background(), name)
+		if err != nil {
+			color.Red("Failed to get the schema of plugin %s: %v", name, err)
+			return nil, err
+		}
+
+		var schema map[string]interface{}
+		err = json.Unmarshal([]byte(content), &schema)
+		if err != nil {
+			color.Red("Failed to parse the schema of plugin %s: %v", name, err)
+			return nil, err
+		}
+		schemas[name] = schema
+	}
+	return schemas, nil
+}
@@ -4,23 +4,55 @@ Copyright © 2023 API7.ai
 package cmd
 
 import (
+	"errors"
+
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+// errDrift is returned by diff --exit-code if there are differences,
+// Execute turns it into the exit code 2.
+var errDrift = errors.New("the local and remote configurations differ")
+
 // newDiffCmd represents the diff command
 func newDiffCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "diff",
 		Short: "Show the differences between the local and existing APISIX configuration",
 		Long:  `Shows the differences in the configuration between the local confguration file and the connected APISIX instance.`,
+		// the errors are printed already
+		SilenceUsage:  true,
+		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			checkConfig()
+			err := checkConfig()
+			if err != nil {
+				return err
+			}
+
+			exitCode, err := cmd.Flags().GetBool("exit-code")
+			if err != nil {
+				color.Red("Failed to get the exit-code flag: %v", err)
+				return err
+			}
 
-			_ = sync(cmd, true)
+			events, err := sync(cmd, true)
+			if err != nil {
+				return err
+			}
+			if exitCode && len(events) > 0 {
+				return errDrift
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringP("file", "f", "adc.yaml", "configuration file path")
+	cmd.Flags().Bool("exit-code", false, "exit with 2 if there are differences, 1 on errors and 0 otherwise")
+	cmd.Flags().String("out", "", "save the differences as a plan file, which can be applied by sync --plan")
+	addSelectorFlag(cmd)
+	addOwnershipFlags(cmd)
+	addResourceTypeFlags(cmd)
+	addIgnoreFieldFlag(cmd)
+	addOutputFormatFlag(cmd)
 	return cmd
 }
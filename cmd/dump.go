@@ -12,7 +12,6 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/api7/adc/pkg/api/apisix"
-	"github.com/api7/adc/pkg/api/apisix/types"
 	"github.com/api7/adc/pkg/common"
 )
 
@@ -23,9 +22,17 @@ func newDumpCmd() *cobra.Command {
 		Short: "Dump the APISIX configuration",
 		Long:  `Dumps the configuration of the connected APISIX instance to a local file.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			checkConfig()
+			err := checkConfig()
+			if err != nil {
+				return err
+			}
+
+			err = usePluginDefaults()
+			if err != nil {
+				return err
+			}
 
-			err := dumpConfiguration(cmd)
+			err = dumpConfiguration(cmd)
 			if err != nil {
 				color.Red(err.Error())
 			}
@@ -34,6 +41,8 @@ func newDumpCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("output", "o", "/dev/stdout", "output file path")
+	addSelectorFlag(cmd)
+	addResourceTypeFlags(cmd)
 
 	return cmd
 }
@@ -53,61 +62,26 @@ func dumpConfiguration(cmd *cobra.Command) error {
 		save = false
 	}
 
-	cluster, err := apisix.NewCluster(context.Background(), rootConfig.ClientConfig)
+	selector, err := getSelector(cmd)
 	if err != nil {
 		return err
 	}
 
-	svcs, err := cluster.Service().List(context.Background())
+	resourceTypes, err := getResourceTypeFilter(cmd)
 	if err != nil {
 		return err
 	}
 
-	routes, err := cluster.Route().List(context.Background())
-	if err != nil {
-		return err
-	}
-
-	consumers, err := cluster.Consumer().List(context.Background())
-	if err != nil {
-		return err
-	}
-
-	ssls, err := cluster.SSL().List(context.Background())
-	if err != nil {
-		return err
-	}
-
-	globalRules, err := cluster.GlobalRule().List(context.Background())
-	if err != nil {
-		return err
-	}
-
-	pluginConfigs, err := cluster.PluginConfig().List(context.Background())
-	if err != nil {
-		return err
-	}
-
-	consumerGroups, err := cluster.ConsumerGroup().List(context.Background())
+	cluster, err := apisix.NewCluster(context.Background(), rootConfig.ClientConfig)
 	if err != nil {
 		return err
 	}
 
-	pluginMetadatas, err := cluster.PluginMetadata().List(context.Background())
+	conf, err := common.GetContentFromRemote(cluster, resourceTypes)
 	if err != nil {
 		return err
 	}
-
-	conf := &types.Configuration{
-		Routes:          routes,
-		Services:        svcs,
-		Consumers:       consumers,
-		SSLs:            ssls,
-		GlobalRules:     globalRules,
-		PluginConfigs:   pluginConfigs,
-		ConsumerGroups:  consumerGroups,
-		PluginMetadatas: pluginMetadatas,
-	}
+	conf = common.FilterConfiguration(conf, selector)
 
 	if save {
 		err = common.SaveAPISIXConfiguration(path, conf)
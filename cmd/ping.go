@@ -20,7 +20,10 @@ func newPingCmd() *cobra.Command {
 		Short: "Verify connectivity with APISIX",
 		Long:  `Pings the configured APISIX instance to verify connectivity.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			checkConfig()
+			err := checkConfig()
+			if err != nil {
+				return err
+			}
 
 			return pingAPISIX()
 		},
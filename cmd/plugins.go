@@ -0,0 +1,114 @@
+/*
+Copyright © 2023 API7.ai
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// newPluginsCmd represents the plugins command
+func newPluginsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugins",
+		Short: "Show the plugins available on APISIX",
+		Long:  `Shows the plugins enabled on the connected APISIX instance and their schemas.`,
+	}
+
+	cmd.PersistentFlags().String("output-format", "yaml", "output format: json or yaml")
+
+	cmd.AddCommand(newPluginsListCmd())
+	cmd.AddCommand(newPluginsSchemaCmd())
+	return cmd
+}
+
+// newPluginsListCmd represents the plugins list command
+func newPluginsListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the plugins enabled on APISIX",
+		Long:  `Lists the names of plugins enabled on the connected APISIX instance.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := checkConfig()
+			if err != nil {
+				return err
+			}
+
+			plugins, err := rootConfig.APISIXCluster.Plugin().List(context.Background())
+			if err != nil {
+				color.Red("Failed to list plugins: %v", err)
+				return err
+			}
+
+			content, err := json.Marshal(plugins)
+			if err != nil {
+				color.Red("Failed to marshal plugins: %v", err)
+				return err
+			}
+			return printJSON(cmd, content)
+		},
+	}
+
+	return cmd
+}
+
+// newPluginsSchemaCmd represents the plugins schema command
+func newPluginsSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema <name>",
+		Short: "Show the schema of a plugin",
+		Long:  `Shows the JSON schema of the plugin on the connected APISIX instance.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := checkConfig()
+			if err != nil {
+				return err
+			}
+
+			schema, err := rootConfig.APISIXCluster.Plugin().Schema(context.Background(), args[0])
+			if err != nil {
+				color.Red("Failed to get the schema of plugin %s: %v", args[0], err)
+				return err
+			}
+			return printJSON(cmd, []byte(schema))
+		},
+	}
+
+	return cmd
+}
+
+// printJSON prints the JSON content in the format specified by the output-format flag.
+func printJSON(cmd *cobra.Command, content []byte) error {
+	format, err := cmd.Flags().GetString("output-format")
+	if err != nil {
+		color.Red("Failed to get output format: %v", err)
+		return err
+	}
+
+	var out []byte
+	switch format {
+	case "json":
+		var buf bytes.Buffer
+		err = json.Indent(&buf, content, "", "  ")
+		out = append(buf.Bytes(), '\n')
+	case "yaml":
+		out, err = yaml.JSONToYAML(content)
+	default:
+		err = fmt.Errorf("unsupported output format: %s", format)
+	}
+	if err != nil {
+		color.Red("Failed to format output: %v", err)
+		return err
+	}
+
+	_, err = fmt.Print(string(out))
+	return err
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2023 API7.ai
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/api7/adc/pkg/common"
+)
+
+// newRestoreCmd represents the restore command
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [backup]",
+		Short: "Restore APISIX configuration from a backup",
+		Long: `Restores the configuration of APISIX from a backup saved by sync.
+
+The backup can be a file path or the name of a file in the backup directory.
+Without a backup, the available backups are listed.`,
+		Args: cobra.MaximumNArgs(1),
+		// the errors are printed already
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := checkConfig()
+			if err != nil {
+				return err
+			}
+
+			dir, err := cmd.Flags().GetString("backup-dir")
+			if err != nil {
+				color.Red("Failed to get the backup directory: %v", err)
+				return err
+			}
+			dir = os.ExpandEnv(dir)
+
+			if len(args) == 0 {
+				return listBackups(dir)
+			}
+
+			path := args[0]
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				path = filepath.Join(dir, args[0])
+			}
+			if _, err := os.Stat(path); err != nil {
+				color.Red("Failed to find the backup: %v", err)
+				return err
+			}
+
+			color.Green("Restoring APISIX configuration from %s", path)
+			_, err = syncFile(cmd, path, false)
+			return err
+		},
+	}
+
+	addSelectorFlag(cmd)
+	addOwnershipFlags(cmd)
+	addResourceTypeFlags(cmd)
+	addIgnoreFieldFlag(cmd)
+	addOutputFormatFlag(cmd)
+	addApplyFlags(cmd)
+
+	return cmd
+}
+
+func listBackups(dir string) error {
+	backups, err := common.ListBackups(dir)
+	if err != nil {
+		color.Red("Failed to list backups: %v", err)
+		return err
+	}
+	if len(backups) == 0 {
+		color.Yellow("No backups found in %s", dir)
+		return nil
+	}
+
+	for _, backup := range backups {
+		fmt.Println(filepath.Base(backup))
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"github.com/fatih/color"
@@ -21,8 +22,9 @@ type Config struct {
 }
 
 var (
-	cfgFile    string
-	rootConfig Config
+	cfgFile       string
+	apisixVersion string
+	rootConfig    Config
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -37,13 +39,18 @@ It can be used to validate, dump, diff, and sync configurations with an APISIX i
 	}
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.adc.yaml)")
+	rootCmd.PersistentFlags().StringVar(&apisixVersion, "apisix-version", "", "APISIX version of the plugin default values (default is the version of the connected APISIX)")
 
 	rootCmd.AddCommand(newConfigureCmd())
 	rootCmd.AddCommand(newPingCmd())
 	rootCmd.AddCommand(newDumpCmd())
 	rootCmd.AddCommand(newDiffCmd())
 	rootCmd.AddCommand(newSyncCmd())
+	rootCmd.AddCommand(newRestoreCmd())
 	rootCmd.AddCommand(newValidateCmd())
+	rootCmd.AddCommand(newPluginsCmd())
+	rootCmd.AddCommand(newDefaultsCmd())
+	rootCmd.AddCommand(newSchemasCmd())
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newOpenAPI2APISIXCmd())
 	return rootCmd
@@ -54,6 +61,9 @@ It can be used to validate, dump, diff, and sync configurations with an APISIX i
 func Execute() {
 	rootCmd := newRootCmd()
 	err := rootCmd.Execute()
+	if errors.Is(err, errDrift) {
+		os.Exit(2)
+	}
 	if err != nil {
 		os.Exit(1)
 	}
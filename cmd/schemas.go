@@ -0,0 +1,142 @@
+/*
+Copyright © 2023 API7.ai
+*/
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/api7/adc/internal/pkg/validator"
+)
+
+// newSchemasCmd represents the schemas command
+func newSchemasCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schemas",
+		Short: "Manage the JSON schemas for offline validation",
+		Long:  `Manages the JSON schemas of resources and plugins, which are embedded in ADC for adc validate --offline.`,
+	}
+
+	cmd.AddCommand(newSchemasGenerateCmd())
+	return cmd
+}
+
+// newSchemasGenerateCmd represents the schemas generate command
+func newSchemasGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate the JSON schemas from APISIX",
+		Long: `Generates the JSON schemas of resources (<resource type>.json) and of plugins enabled on APISIX
+(plugins/<name>.json, and plugins/<name>.consumer.json for the consumer schemas) to the output directory.
+The version of APISIX is written to the VERSION file. The existing plugin schemas are removed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, err := cmd.Flags().GetString("output-dir")
+			if err != nil {
+				color.Red("Failed to get the output directory: %v", err)
+				return err
+			}
+
+			err = checkConfig()
+			if err != nil {
+				return err
+			}
+			return generateSchemas(output)
+		},
+	}
+
+	cmd.Flags().StringP("output-dir", "o", "schemas", "output directory of the schemas")
+	return cmd
+}
+
+// generateSchemas writes the schemas of resources and plugins of the connected APISIX to the directory.
+func generateSchemas(dir string) error {
+	ctx := context.Background()
+	cluster := rootConfig.APISIXCluster
+
+	version, err := cluster.Version(ctx)
+	if err != nil {
+		color.Red("Failed to get the APISIX version: %v", err)
+		return err
+	}
+
+	// the plugins removed from APISIX must not be kept
+	pluginDir := filepath.Join(dir, "plugins")
+	err = os.RemoveAll(pluginDir)
+	if err == nil {
+		err = os.MkdirAll(pluginDir, 0755)
+	}
+	if err != nil {
+		color.Red("Failed to create the output directory: %v", err)
+		return err
+	}
+
+	for _, resourceType := range validator.SchemaResourceTypes {
+		content, err := cluster.Schema(ctx, resourceType)
+		if err != nil {
+			color.Red("Failed to get the schema of %s: %v", resourceType, err)
+			return err
+		}
+		err = writeSchema(filepath.Join(dir, resourceType+".json"), content)
+		if err != nil {
+			return err
+		}
+	}
+
+	plugins, err := cluster.Plugin().List(ctx)
+	if err != nil {
+		color.Red("Failed to list plugins: %v", err)
+		return err
+	}
+	for _, name := range plugins {
+		content, err := cluster.Plugin().Schema(ctx, name)
+		if err != nil {
+			color.Red("Failed to get the schema of plugin %s: %v", name, err)
+			return err
+		}
+		err = writeSchema(filepath.Join(pluginDir, name+".json"), content)
+		if err != nil {
+			return err
+		}
+
+		// APISIX responds with an error if the plugin has no consumer schema
+		content, err = cluster.Plugin().ConsumerSchema(ctx, name)
+		if err != nil {
+			continue
+		}
+		err = writeSchema(filepath.Join(pluginDir, name+".consumer.json"), content)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "VERSION"), []byte(strings.TrimSpace(version)+"\n"), 0644)
+	if err != nil {
+		color.Red("Failed to write the APISIX version: %v", err)
+		return err
+	}
+
+	color.Green("Generated the schemas of %d resource types and %d plugins of APISIX %s to %s", len(validator.SchemaResourceTypes), len(plugins), version, dir)
+	return nil
+}
+
+// writeSchema normalizes the schema returned by APISIX and writes it to the file
+func writeSchema(path, content string) error {
+	schema, err := validator.NormalizeSchema(content)
+	if err != nil {
+		color.Red("Failed to parse the schema %s: %v", path, err)
+		return err
+	}
+	err = os.WriteFile(path, schema, 0644)
+	if err != nil {
+		color.Red("Failed to write the schema %s: %v", path, err)
+		return err
+	}
+	return nil
+}
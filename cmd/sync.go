@@ -4,14 +4,19 @@ Copyright © 2023 API7.ai
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strings"
-	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/api7/adc/internal/pkg/differ"
+	"github.com/api7/adc/internal/pkg/executor"
+	"github.com/api7/adc/internal/pkg/validator"
+	"github.com/api7/adc/pkg/api/apisix"
+	"github.com/api7/adc/pkg/api/apisix/types"
 	"github.com/api7/adc/pkg/common"
 	"github.com/api7/adc/pkg/data"
 )
@@ -22,93 +27,482 @@ func newSyncCmd() *cobra.Command {
 		Use:   "sync",
 		Short: "Sync local configuration to APISIX",
 		Long:  `Syncs the configuration in adc.yaml (or other provided file) to APISIX.`,
+		// the errors are printed already
+		SilenceUsage:  true,
+		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			checkConfig()
+			err := checkConfig()
+			if err != nil {
+				return err
+			}
 
 			// TODO: add validate before sync
-			_ = sync(cmd, false)
-			return nil
+			_, err = sync(cmd, false)
+			return err
 		},
 	}
 
 	cmd.Flags().StringP("file", "f", "adc.yaml", "configuration file path")
+	cmd.Flags().String("plan", "", "apply the plan file made by diff instead of the configuration file")
+	addSelectorFlag(cmd)
+	addOwnershipFlags(cmd)
+	addResourceTypeFlags(cmd)
+	addIgnoreFieldFlag(cmd)
+	addApplyFlags(cmd)
+	addOutputFormatFlag(cmd)
 
 	return cmd
 }
 
-func sync(cmd *cobra.Command, dryRun bool) error {
+// addApplyFlags adds the flags about how changes are applied to APISIX
+func addApplyFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("parallelism", 10, "maximum number of changes applied to APISIX at the same time")
+	cmd.Flags().Bool("rollback", false, "revert the applied changes when the sync fails")
+	cmd.Flags().Bool("backup", true, "back up the remote configuration before applying changes")
+	cmd.Flags().String("backup-dir", "$HOME/.adc/backups", "directory of the backup files")
+	cmd.Flags().Int("backup-retention", 10, "number of backup files to keep, 0 to keep all")
+}
+
+// sync syncs the configuration to APISIX and returns the events of the differences,
+// it only shows the differences if dryRun is true.
+func sync(cmd *cobra.Command, dryRun bool) ([]*data.Event, error) {
+	if !dryRun {
+		planFile, err := cmd.Flags().GetString("plan")
+		if err != nil {
+			color.Red("Failed to get the plan file: %v", err)
+			return nil, err
+		}
+		if planFile != "" {
+			return syncPlan(cmd, planFile)
+		}
+	}
+
 	file, err := cmd.Flags().GetString("file")
 	if err != nil {
 		color.Red("Failed to get the configuration file: %v", err)
-		return err
+		return nil, err
+	}
+
+	return syncFile(cmd, file, dryRun)
+}
+
+// syncFile syncs the configuration file to APISIX and returns the events of
+// the differences, it only shows the differences if dryRun is true.
+func syncFile(cmd *cobra.Command, file string, dryRun bool) ([]*data.Event, error) {
+	p, err := newEventPrinter(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	err = usePluginDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := getSelector(cmd)
+	if err != nil {
+		return nil, err
 	}
 
 	config, err := common.GetContentFromFile(file)
 	if err != nil {
 		color.Red("Failed to read configuration file: %v", err)
-		return err
+		return nil, err
 	}
+	config = common.FilterConfiguration(config, selector)
 
-	remoteConfig, err := common.GetContentFromRemote(rootConfig.APISIXCluster)
+	resourceTypes, err := getResourceTypeFilter(cmd)
+	if err != nil {
+		return nil, err
+	}
+	config = common.FilterResourceTypes(config, resourceTypes)
+
+	ignoreFields, err := cmd.Flags().GetStringSlice("ignore-field")
+	if err != nil {
+		color.Red("Failed to get the ignored fields: %v", err)
+		return nil, err
+	}
+	config.IgnoreFields = append(config.IgnoreFields, ignoreFields...)
+
+	ownerLabels, err := getOwnerLabels(cmd, config.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ownerLabels != nil {
+		common.AddLabels(config, ownerLabels)
+	}
+
+	if !dryRun {
+		err = checkPlugins(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	remoteConfig, err := common.GetContentFromRemote(rootConfig.APISIXCluster, resourceTypes)
 	if err != nil {
 		color.Red("Failed to get remote configuration: %v", err)
-		return err
+		return nil, err
 	}
 
-	d, err := differ.NewDiffer(config, remoteConfig)
+	// the remote configuration is kept as a whole for backup
+	remote := common.FilterConfiguration(remoteConfig, selector)
+	d, err := differ.NewDiffer(config, remote)
 	if err != nil {
 		color.Red("Failed to create a Differ object: %v", err)
-		return err
+		return nil, err
 	}
+	d.SetOwnerLabels(ownerLabels)
+	d.SetResourceTypeFilter(resourceTypes)
 
 	events, err := d.Diff()
 	if err != nil {
 		color.Red("Failed to compare local and remote configuration: %v", err)
-		return err
+		return nil, err
+	}
+
+	if dryRun {
+		for _, event := range events {
+			err = p.print(event)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		err = applyEvents(cmd, events, remoteConfig, resourceTypes, p)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	var summary struct {
-		created int
-		updated int
-		deleted int
+	for _, event := range d.Unmanaged() {
+		color.Yellow("unmanaged %s: \"%s\", skip deleting", event.ResourceType, apisix.GetResourceUniqueKey(event.OldValue))
 	}
 
-	for _, event := range events {
-		if event.Option == data.CreateOption {
-			summary.created++
-		} else if event.Option == data.UpdateOption {
-			summary.updated++
-		} else if event.Option == data.DeleteOption {
-			summary.deleted++
-		}
+	err = p.flush()
+	if err != nil {
+		return nil, err
+	}
+	p.summary()
 
-		str, err := event.Output()
+	if dryRun {
+		err = savePlan(cmd, events, remote)
 		if err != nil {
-			color.Red("Failed to get output of the event: %v", err)
-			return err
+			return nil, err
 		}
+	}
+	return events, nil
+}
+
+// savePlan saves the events to the plan file if it's specified
+func savePlan(cmd *cobra.Command, events []*data.Event, remote *types.Configuration) error {
+	path, err := cmd.Flags().GetString("out")
+	if err != nil {
+		color.Red("Failed to get the plan file: %v", err)
+		return err
+	}
+	if path == "" {
+		return nil
+	}
+
+	plan, err := data.NewPlan(events, remote)
+	if err != nil {
+		color.Red("Failed to create the plan: %v", err)
+		return err
+	}
+
+	err = plan.Save(path)
+	if err != nil {
+		color.Red("Failed to save the plan: %v", err)
+		return err
+	}
+	color.Green("Saved the plan to %s", path)
+
+	return nil
+}
+
+// syncPlan applies the events of the plan file to APISIX, if the remote
+// configuration hasn't changed since the plan was made.
+func syncPlan(cmd *cobra.Command, path string) ([]*data.Event, error) {
+	p, err := newEventPrinter(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	err = usePluginDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := data.ReadPlan(path)
+	if err != nil {
+		color.Red("Failed to read the plan file: %v", err)
+		return nil, err
+	}
+
+	selector, err := getSelector(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceTypes, err := getResourceTypeFilter(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkPlugins(plan.Configuration())
+	if err != nil {
+		return nil, err
+	}
+
+	remoteConfig, err := common.GetContentFromRemote(rootConfig.APISIXCluster, resourceTypes)
+	if err != nil {
+		color.Red("Failed to get remote configuration: %v", err)
+		return nil, err
+	}
+
+	fingerprint, err := data.Fingerprint(common.FilterConfiguration(remoteConfig, selector))
+	if err != nil {
+		color.Red("Failed to get the fingerprint of remote configuration: %v", err)
+		return nil, err
+	}
+	if fingerprint != plan.Fingerprint {
+		color.Red("The remote configuration has changed since the plan was made, please make the plan again. " +
+			"The --selector and resource type flags must be the same as the ones used by diff.")
+		return nil, errors.New("the remote configuration has drifted from the plan")
+	}
+
+	err = applyEvents(cmd, plan.Events, remoteConfig, resourceTypes, p)
+	if err != nil {
+		return nil, err
+	}
+	err = p.flush()
+	if err != nil {
+		return nil, err
+	}
+	p.summary()
+
+	return plan.Events, nil
+}
+
+// applyEvents backs up the remote configuration and applies the events
+func applyEvents(cmd *cobra.Command, events []*data.Event, remoteConfig *types.Configuration,
+	resourceTypes *data.ResourceTypeFilter, p *eventPrinter) error {
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	if err != nil {
+		color.Red("Failed to get the parallelism: %v", err)
+		return err
+	}
+
+	rollback, err := cmd.Flags().GetBool("rollback")
+	if err != nil {
+		color.Red("Failed to get the rollback flag: %v", err)
+		return err
+	}
 
-		if !dryRun {
-			err = event.Apply(rootConfig.APISIXCluster)
+	e, err := executor.NewExecutor(rootConfig.APISIXCluster, parallelism)
+	if err != nil {
+		color.Red("Failed to create an Executor object: %v", err)
+		return err
+	}
+
+	if len(events) > 0 {
+		// the backup always contains all the resource types, or
+		// restoring it would delete the resource types not fetched
+		if resourceTypes != nil {
+			remoteConfig, err = common.GetContentFromRemote(rootConfig.APISIXCluster, nil)
 			if err != nil {
-				color.Red("Failed to apply configuration: %v", err)
+				color.Red("Failed to get remote configuration: %v", err)
 				return err
 			}
-			time.Sleep(100 * time.Millisecond)
 		}
+		err = backup(cmd, remoteConfig)
+		if err != nil {
+			return err
+		}
+	}
 
-		for _, line := range strings.Split(str, "\n") {
-			if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "creating") {
-				color.Green(line)
-			} else if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "deleting") {
-				color.Red(line)
-			} else {
-				fmt.Println(line)
-			}
+	var applied []*data.Event
+	err = e.Execute(events, func(event *data.Event, err error) {
+		if err != nil {
+			color.Red("Failed to apply configuration: %v", err)
+			return
+		}
+		applied = append(applied, event)
+		_ = p.print(event)
+	})
+	if err != nil {
+		if rollback {
+			rollbackEvents(e, applied)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// eventPrinter prints the events and counts them for the summary, the
+// events are printed at once by flush in the machine-readable formats.
+type eventPrinter struct {
+	format string
+	events []*data.Event
+
+	created int
+	updated int
+	deleted int
+}
+
+func newEventPrinter(cmd *cobra.Command) (*eventPrinter, error) {
+	format, err := cmd.Flags().GetString("output-format")
+	if err != nil {
+		color.Red("Failed to get the output format: %v", err)
+		return nil, err
+	}
+
+	valid := false
+	for _, f := range data.OutputFormats {
+		valid = valid || f == format
+	}
+	if !valid {
+		err = fmt.Errorf("unsupported output format '%s'", format)
+		color.Red("Failed to create the event printer: %v", err)
+		return nil, err
+	}
+
+	if format != data.TextFormat {
+		// keep stdout for the formatted events only
+		color.Output = os.Stderr
+	}
+
+	return &eventPrinter{format: format}, nil
+}
+
+func (p *eventPrinter) print(event *data.Event) error {
+	if event.Option == data.CreateOption {
+		p.created++
+	} else if event.Option == data.UpdateOption {
+		p.updated++
+	} else if event.Option == data.DeleteOption {
+		p.deleted++
+	}
+
+	if p.format != data.TextFormat {
+		p.events = append(p.events, event)
+		return nil
+	}
+
+	str, err := event.Output()
+	if err != nil {
+		color.Red("Failed to get output of the event: %v", err)
+		return err
+	}
+
+	for _, line := range strings.Split(str, "\n") {
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "creating") {
+			color.Green(line)
+		} else if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "deleting") {
+			color.Red(line)
+		} else {
+			fmt.Println(line)
 		}
 	}
+	return nil
+}
 
-	color.Green("Summary: created %d, updated %d, deleted %d", summary.created, summary.updated, summary.deleted)
+// flush prints the events in the machine-readable formats
+func (p *eventPrinter) flush() error {
+	if p.format == data.TextFormat {
+		return nil
+	}
+
+	output, err := data.FormatEvents(p.events, p.format)
+	if err != nil {
+		color.Red("Failed to format the events: %v", err)
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
 
+func (p *eventPrinter) summary() {
+	color.Green("Summary: created %d, updated %d, deleted %d", p.created, p.updated, p.deleted)
+}
+
+// backup saves the remote configuration before it's changed, if enabled
+func backup(cmd *cobra.Command, remoteConfig *types.Configuration) error {
+	enabled, err := cmd.Flags().GetBool("backup")
+	if err != nil {
+		color.Red("Failed to get the backup flag: %v", err)
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	dir, err := cmd.Flags().GetString("backup-dir")
+	if err != nil {
+		color.Red("Failed to get the backup directory: %v", err)
+		return err
+	}
+
+	retention, err := cmd.Flags().GetInt("backup-retention")
+	if err != nil {
+		color.Red("Failed to get the backup retention: %v", err)
+		return err
+	}
+
+	path, err := common.SaveBackup(os.ExpandEnv(dir), retention, remoteConfig)
+	if err != nil {
+		color.Red("Failed to back up remote configuration: %v", err)
+		return err
+	}
+	color.Green("Backed up remote configuration to %s", path)
+
+	return nil
+}
+
+// rollbackEvents reverts the applied events and reports the result
+func rollbackEvents(e *executor.Executor, applied []*data.Event) {
+	color.Yellow("Rolling back %d applied changes", len(applied))
+
+	var reverted []string
+	var failed []string
+	e.Rollback(applied, func(event *data.Event, err error) {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", event.Summary(), err))
+		} else {
+			reverted = append(reverted, event.Summary())
+		}
+	})
+
+	for _, msg := range reverted {
+		color.Green("reverted %s", msg)
+	}
+	for _, msg := range failed {
+		color.Red("failed to revert %s", msg)
+	}
+	if len(failed) > 0 {
+		color.Red("Rollback summary: reverted %d, failed %d, the changes above need to be fixed manually", len(reverted), len(failed))
+	} else {
+		color.Green("Rollback summary: reverted %d", len(reverted))
+	}
+}
+
+// checkPlugins makes sure that all plugins used by the configuration are
+// enabled on APISIX before any change is applied.
+func checkPlugins(config *types.Configuration) error {
+	v, err := validator.NewValidator(config, rootConfig.APISIXCluster)
+	if err != nil {
+		color.Red("Failed to create validator: %v", err)
+		return err
+	}
+	errs := v.CheckPlugins()
+	if len(errs) > 0 {
+		color.Red("Some plugins are not available on APISIX:")
+		for _, err := range errs {
+			color.Red(err.Error())
+		}
+		return validator.ErrorsWrapper{Errors: errs}
+	}
 	return nil
 }
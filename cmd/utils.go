@@ -1,14 +1,155 @@
 package cmd
 
 import (
-	"os"
+	"context"
+	"errors"
+	"strings"
 
 	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/api7/adc/pkg/api/apisix"
+	"github.com/api7/adc/pkg/api/apisix/types"
+	"github.com/api7/adc/pkg/common"
+	"github.com/api7/adc/pkg/data"
 )
 
-func checkConfig() {
+// checkConfig returns an error if ADC isn't configured or the APISIX cluster
+// can't be created, so that the commands fail instead of reporting success
+// without connecting to APISIX.
+func checkConfig() error {
 	if rootConfig.Server == "" || rootConfig.Token == "" {
-		color.Yellow("ADC isn't configured, run `adc configure` to configure ADC.")
-		os.Exit(0)
+		color.Red("ADC isn't configured, run `adc configure` to configure ADC.")
+		return errors.New("ADC isn't configured")
+	}
+	return ensureCluster()
+}
+
+// ensureCluster creates the APISIX cluster client if it failed to be
+// created in initConfig, and returns the error instead.
+func ensureCluster() error {
+	if rootConfig.APISIXCluster != nil {
+		return nil
+	}
+	cluster, err := apisix.NewCluster(context.Background(), rootConfig.ClientConfig)
+	if err != nil {
+		color.Red("Failed to create a new cluster: %v", err)
+		return err
+	}
+	rootConfig.APISIXCluster = cluster
+	return nil
+}
+
+// usePluginDefaults selects the plugin default values of the APISIX version
+// set by --apisix-version, or the version of the connected APISIX instance.
+// It must be called before reading any configuration.
+func usePluginDefaults() error {
+	version := apisixVersion
+	if version == "" {
+		err := ensureCluster()
+		if err != nil {
+			return err
+		}
+
+		detected, err := rootConfig.APISIXCluster.Version(context.Background())
+		if err != nil {
+			color.Yellow("Failed to detect the APISIX version, using the plugin default values of %s: %v", types.DefaultAPISIXVersion, err)
+			return nil
+		}
+		version = detected
+	}
+
+	selected, err := types.UseAPISIXVersion(version)
+	if err != nil {
+		color.Red("Failed to select the plugin default values: %v", err)
+		return err
+	}
+	if !strings.HasPrefix(version+".", selected+".") {
+		color.Yellow("No plugin default values of APISIX %s, using the nearest version %s", version, selected)
+	}
+	return nil
+}
+
+func addSelectorFlag(cmd *cobra.Command) {
+	cmd.Flags().String("selector", "", "only handle the resources with all the labels, e.g. key=value[,key2=value2]")
+}
+
+func getSelector(cmd *cobra.Command) (common.Selector, error) {
+	s, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		color.Red("Failed to get the selector: %v", err)
+		return nil, err
+	}
+
+	selector, err := common.ParseSelector(s)
+	if err != nil {
+		color.Red("Failed to parse the selector: %v", err)
+		return nil, err
+	}
+	return selector, nil
+}
+
+func addOwnershipFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("ownership", false, "stamp resources with the owner label and only delete the owned resources")
+	cmd.Flags().String("owner-label", "managed-by=adc", "the owner label in the ownership mode, in the form of key=value")
+}
+
+// getOwnerLabels returns the labels stamped in the ownership mode, or nil if it's disabled
+func getOwnerLabels(cmd *cobra.Command, name string) (types.Labels, error) {
+	enabled, err := cmd.Flags().GetBool("ownership")
+	if err != nil {
+		color.Red("Failed to get the ownership flag: %v", err)
+		return nil, err
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	ownerLabel, err := cmd.Flags().GetString("owner-label")
+	if err != nil {
+		color.Red("Failed to get the owner label: %v", err)
+		return nil, err
 	}
+
+	labels, err := common.OwnerLabels(ownerLabel, name)
+	if err != nil {
+		color.Red("Failed to parse the owner label: %v", err)
+		return nil, err
+	}
+	return labels, nil
+}
+
+func addResourceTypeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("include-resource-type", nil, "only handle the resource types, e.g. route,service")
+	cmd.Flags().StringSlice("exclude-resource-type", nil, "don't handle the resource types, e.g. consumer,credential")
+}
+
+// getResourceTypeFilter returns the filter of resource types, or nil if all types are selected
+func getResourceTypeFilter(cmd *cobra.Command) (*data.ResourceTypeFilter, error) {
+	include, err := cmd.Flags().GetStringSlice("include-resource-type")
+	if err != nil {
+		color.Red("Failed to get the included resource types: %v", err)
+		return nil, err
+	}
+
+	exclude, err := cmd.Flags().GetStringSlice("exclude-resource-type")
+	if err != nil {
+		color.Red("Failed to get the excluded resource types: %v", err)
+		return nil, err
+	}
+
+	filter, err := data.NewResourceTypeFilter(include, exclude)
+	if err != nil {
+		color.Red("Failed to parse the resource types: %v", err)
+		return nil, err
+	}
+	return filter, nil
+}
+
+func addIgnoreFieldFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("ignore-field", nil, "ignore the fields managed out of band, e.g. service.*.upstream.nodes")
+}
+
+func addOutputFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String("output-format", data.TextFormat, "output format of the changes: "+strings.Join(data.OutputFormats, "|"))
 }
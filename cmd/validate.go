@@ -21,9 +21,26 @@ func newValidateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate the provided configuration file",
-		Long:  `Validates the provided configuration file with the connected APISIX instance.`,
+		Long: `Validates the provided configuration file with the connected APISIX instance.
+
+With --offline, the routes, services, upstreams, consumers, SSLs and plugin configs are validated
+with the JSON schemas embedded in ADC instead, without connecting to APISIX. The plugins without
+embedded schemas, e.g. custom plugins, fail the validation.`,
+		// the errors are printed already
+		SilenceUsage:  true,
+		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			checkConfig()
+			offline, err := cmd.Flags().GetBool("offline")
+			if err != nil {
+				color.Red("Failed to get the offline flag: %v", err)
+				return err
+			}
+			if !offline {
+				err = checkConfig()
+				if err != nil {
+					return err
+				}
+			}
 
 			file, err := cmd.Flags().GetString("file")
 			if err != nil {
@@ -35,11 +52,26 @@ func newValidateCmd() *cobra.Command {
 				return nil
 			}
 
+			resourceTypes, err := getResourceTypeFilter(cmd)
+			if err != nil {
+				return err
+			}
+
+			// the plugin default values of the latest version are used offline,
+			// unless the version is set by --apisix-version
+			if !offline || apisixVersion != "" {
+				err = usePluginDefaults()
+				if err != nil {
+					return err
+				}
+			}
+
 			d, err := common.GetContentFromFile(file)
 			if err != nil {
 				color.Red("Failed to read configuration file: %v", err)
 				return err
 			}
+			d = common.FilterResourceTypes(d, resourceTypes)
 
 			msg := fmt.Sprintf("Read configuration file successfully: config name: %v, version: %v", d.Name, d.Version)
 			changed := false
@@ -47,10 +79,18 @@ func newValidateCmd() *cobra.Command {
 				msg += fmt.Sprintf(", routes: %v", len(d.Routes))
 				changed = true
 			}
+			if len(d.StreamRoutes) > 0 {
+				msg += fmt.Sprintf(", stream_routes: %v", len(d.StreamRoutes))
+				changed = true
+			}
 			if len(d.Services) > 0 {
 				msg += fmt.Sprintf(", services: %v", len(d.Services))
 				changed = true
 			}
+			if len(d.Upstreams) > 0 {
+				msg += fmt.Sprintf(", upstreams: %v", len(d.Upstreams))
+				changed = true
+			}
 			if len(d.Consumers) > 0 {
 				msg += fmt.Sprintf(", consumers: %v", len(d.Consumers))
 				changed = true
@@ -71,6 +111,14 @@ func newValidateCmd() *cobra.Command {
 				msg += fmt.Sprintf(", consumer_groups: %v", len(d.ConsumerGroups))
 				changed = true
 			}
+			if len(d.Protos) > 0 {
+				msg += fmt.Sprintf(", protos: %v", len(d.Protos))
+				changed = true
+			}
+			if len(d.Secrets) > 0 {
+				msg += fmt.Sprintf(", secrets: %v", len(d.Secrets))
+				changed = true
+			}
 			// TODO: enable this when APISIX supports
 			//if len(d.PluginMetadatas) > 0 {
 			//	msg += fmt.Sprintf(", plugin_metadatas: %v", len(d.PluginMetadatas))
@@ -82,6 +130,9 @@ func newValidateCmd() *cobra.Command {
 			msg += "."
 			color.Green(msg)
 
+			if offline {
+				return validateOffline(d)
+			}
 			err = validateContent(d)
 			if err != nil {
 				color.Red("Failed to validate configuration file: %v", err)
@@ -92,6 +143,8 @@ func newValidateCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("file", "f", "adc.yaml", "configuration file path")
+	cmd.Flags().Bool("offline", false, "validate with the embedded JSON schemas without connecting to APISIX")
+	addResourceTypeFlags(cmd)
 
 	return cmd
 }
@@ -107,7 +160,8 @@ func validateContent(c *types.Configuration) error {
 		color.Red("Failed to create validator: %v", err)
 		return err
 	}
-	errs := v.Validate()
+	errs := v.CheckPlugins()
+	errs = append(errs, v.Validate()...)
 	if len(errs) > 0 {
 		color.Red("Some validation failed:")
 		for _, err := range errs {
@@ -118,3 +172,23 @@ func validateContent(c *types.Configuration) error {
 	}
 	return nil
 }
+
+// validateOffline validates the content of the configuration file with the embedded
+// JSON schemas, it returns an error if the validation fails, e.g. for pre-commit hooks.
+func validateOffline(c *types.Configuration) error {
+	v, err := validator.NewValidator(c, nil)
+	if err != nil {
+		color.Red("Failed to create validator: %v", err)
+		return err
+	}
+	errs := v.ValidateOffline()
+	if len(errs) > 0 {
+		color.Red("Some validation failed:")
+		for _, err := range errs {
+			color.Red(err.Error())
+		}
+		return validator.ErrorsWrapper{Errors: errs}
+	}
+	color.Green("Successfully validated configuration file!")
+	return nil
+}
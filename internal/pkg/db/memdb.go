@@ -21,6 +21,16 @@ var schema = &memdb.DBSchema{
 				},
 			},
 		},
+		"upstreams": {
+			Name: "upstreams",
+			Indexes: map[string]*memdb.IndexSchema{
+				"id": {
+					Name:    "id",
+					Unique:  true,
+					Indexer: &memdb.StringFieldIndex{Field: "ID"},
+				},
+			},
+		},
 		"routes": {
 			Name: "routes",
 			Indexes: map[string]*memdb.IndexSchema{
@@ -31,6 +41,16 @@ var schema = &memdb.DBSchema{
 				},
 			},
 		},
+		"stream_routes": {
+			Name: "stream_routes",
+			Indexes: map[string]*memdb.IndexSchema{
+				"id": {
+					Name:    "id",
+					Unique:  true,
+					Indexer: &memdb.StringFieldIndex{Field: "ID"},
+				},
+			},
+		},
 		"consumers": {
 			Name: "consumers",
 			Indexes: map[string]*memdb.IndexSchema{
@@ -41,6 +61,21 @@ var schema = &memdb.DBSchema{
 				},
 			},
 		},
+		"credentials": {
+			Name: "credentials",
+			Indexes: map[string]*memdb.IndexSchema{
+				"id": {
+					Name:   "id",
+					Unique: true,
+					Indexer: &memdb.CompoundIndex{
+						Indexes: []memdb.Indexer{
+							&memdb.StringFieldIndex{Field: "Consumer"},
+							&memdb.StringFieldIndex{Field: "ID"},
+						},
+					},
+				},
+			},
+		},
 		"ssls": {
 			Name: "ssls",
 			Indexes: map[string]*memdb.IndexSchema{
@@ -91,6 +126,31 @@ var schema = &memdb.DBSchema{
 				},
 			},
 		},
+		"secrets": {
+			Name: "secrets",
+			Indexes: map[string]*memdb.IndexSchema{
+				"id": {
+					Name:   "id",
+					Unique: true,
+					Indexer: &memdb.CompoundIndex{
+						Indexes: []memdb.Indexer{
+							&memdb.StringFieldIndex{Field: "Manager"},
+							&memdb.StringFieldIndex{Field: "ID"},
+						},
+					},
+				},
+			},
+		},
+		"protos": {
+			Name: "protos",
+			Indexes: map[string]*memdb.IndexSchema{
+				"id": {
+					Name:    "id",
+					Unique:  true,
+					Indexer: &memdb.StringFieldIndex{Field: "ID"},
+				},
+			},
+		},
 	},
 }
 
@@ -119,6 +179,13 @@ func NewMemDB(config *types.Configuration) (*DB, error) {
 		}
 	}
 
+	for _, upstream := range config.Upstreams {
+		err = txn.Insert("upstreams", upstream)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for _, routes := range config.Routes {
 		err = txn.Insert("routes", routes)
 		if err != nil {
@@ -126,11 +193,25 @@ func NewMemDB(config *types.Configuration) (*DB, error) {
 		}
 	}
 
+	for _, streamRoute := range config.StreamRoutes {
+		err = txn.Insert("stream_routes", streamRoute)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for _, consumers := range config.Consumers {
 		err = txn.Insert("consumers", consumers)
 		if err != nil {
 			return nil, err
 		}
+
+		for _, credential := range consumers.Credentials {
+			err = txn.Insert("credentials", credential)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	for _, ssls := range config.SSLs {
@@ -168,6 +249,20 @@ func NewMemDB(config *types.Configuration) (*DB, error) {
 		}
 	}
 
+	for _, proto := range config.Protos {
+		err = txn.Insert("protos", proto)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, secret := range config.Secrets {
+		err = txn.Insert("secrets", secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	txn.Commit()
 
 	return &DB{memDB: db}, nil
@@ -190,14 +285,35 @@ func (db *DB) GetServiceByID(id string) (*types.Service, error) {
 	return getByID[types.Service](db, "services", id)
 }
 
+func (db *DB) GetUpstreamByID(id string) (*types.Upstream, error) {
+	return getByID[types.Upstream](db, "upstreams", id)
+}
+
 func (db *DB) GetRouteByID(id string) (*types.Route, error) {
 	return getByID[types.Route](db, "routes", id)
 }
 
+func (db *DB) GetStreamRouteByID(id string) (*types.StreamRoute, error) {
+	return getByID[types.StreamRoute](db, "stream_routes", id)
+}
+
 func (db *DB) GetConsumerByID(username string) (*types.Consumer, error) {
 	return getByID[types.Consumer](db, "consumers", username)
 }
 
+func (db *DB) GetCredentialByID(consumer, id string) (*types.Credential, error) {
+	obj, err := db.memDB.Txn(false).First("credentials", "id", consumer, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if obj == nil {
+		return nil, NotFound
+	}
+
+	return obj.(*types.Credential), err
+}
+
 func (db *DB) GetSSLByID(id string) (*types.SSL, error) {
 	return getByID[types.SSL](db, "ssls", id)
 }
@@ -217,3 +333,20 @@ func (db *DB) GetConsumerGroupByID(id string) (*types.ConsumerGroup, error) {
 func (db *DB) GetPluginMetadataByID(id string) (*types.PluginMetadata, error) {
 	return getByID[types.PluginMetadata](db, "plugin_metadatas", id)
 }
+
+func (db *DB) GetProtoByID(id string) (*types.Proto, error) {
+	return getByID[types.Proto](db, "protos", id)
+}
+
+func (db *DB) GetSecretByID(manager, id string) (*types.Secret, error) {
+	obj, err := db.memDB.Txn(false).First("secrets", "id", manager, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if obj == nil {
+		return nil, NotFound
+	}
+
+	return obj.(*types.Secret), err
+}
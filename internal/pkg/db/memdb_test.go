@@ -30,6 +30,16 @@ var (
 		},
 	}
 
+	upstream = &types.Upstream{
+		ID:   "upstream",
+		Name: "upstream",
+		Nodes: []types.UpstreamNode{
+			{
+				Host: "httpbin.org",
+			},
+		},
+	}
+
 	route = &types.Route{
 		ID:   "route",
 		Name: "route",
@@ -97,3 +107,31 @@ func TestGetRouteByID(t *testing.T) {
 	assert.Nil(t, err, "check the error")
 	assert.Equal(t, route, route1, "check the route")
 }
+
+func TestGetUpstreamByID(t *testing.T) {
+	// Test Case 1: get upstream by id
+	config := types.Configuration{
+		Upstreams: []*types.Upstream{upstream},
+	}
+
+	db, _ := NewMemDB(&config)
+	upstream1, err := db.GetUpstreamByID("upstream")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, upstream, upstream1, "check the upstream")
+
+	// Test Case 2: get upstream by id (not found)
+	_, err = db.GetUpstreamByID("not-found")
+	assert.Equal(t, NotFound, err, "check the error")
+
+	// Test Case 3: Upstream don't have id
+	upstream2 := *upstream
+	upstream2.ID = ""
+	config = types.Configuration{
+		Upstreams: []*types.Upstream{&upstream2},
+	}
+
+	db, _ = NewMemDB(&config)
+	upstream1, err = db.GetUpstreamByID("upstream")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, upstream, upstream1, "check the upstream")
+}
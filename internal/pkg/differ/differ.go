@@ -1,66 +1,29 @@
 package differ
 
 import (
-	"fmt"
 	"reflect"
-	"sort"
 
 	"github.com/api7/adc/internal/pkg/db"
 	"github.com/api7/adc/pkg/api/apisix/types"
 	"github.com/api7/adc/pkg/data"
 )
 
-var _orderIndex = -1
-
-func _order() int {
-	_orderIndex += 1
-	return _orderIndex
-}
-
-func _key(typ data.ResourceType, option int) string {
-	return fmt.Sprintf("%s:%d", typ, option)
-}
-
-// order is the events order to ensure the data dependency. Higher takes priority
-// route requires: service, plugin config, consumer (soft require)
-// consumer requires: consumer group
-// The dependent resources should be created/updated first but deleted later
-var order = map[string]int{
-	_key(data.ServiceResourceType, data.DeleteOption):       _order(),
-	_key(data.PluginConfigResourceType, data.DeleteOption):  _order(),
-	_key(data.ConsumerGroupResourceType, data.DeleteOption): _order(),
-	_key(data.ConsumerResourceType, data.DeleteOption):      _order(),
-	_key(data.RouteResourceType, data.DeleteOption):         _order(),
-
-	_key(data.RouteResourceType, data.UpdateOption):         _order(),
-	_key(data.ServiceResourceType, data.UpdateOption):       _order(),
-	_key(data.PluginConfigResourceType, data.UpdateOption):  _order(),
-	_key(data.ConsumerResourceType, data.UpdateOption):      _order(),
-	_key(data.ConsumerGroupResourceType, data.UpdateOption): _order(),
-
-	_key(data.RouteResourceType, data.CreateOption):         _order(),
-	_key(data.ServiceResourceType, data.CreateOption):       _order(),
-	_key(data.PluginConfigResourceType, data.CreateOption):  _order(),
-	_key(data.ConsumerResourceType, data.CreateOption):      _order(),
-	_key(data.ConsumerGroupResourceType, data.CreateOption): _order(),
-
-	// no dependency
-	_key(data.SSLResourceType, data.DeleteOption):            _order(),
-	_key(data.SSLResourceType, data.CreateOption):            _order(),
-	_key(data.SSLResourceType, data.UpdateOption):            _order(),
-	_key(data.GlobalRuleResourceType, data.DeleteOption):     _order(),
-	_key(data.GlobalRuleResourceType, data.CreateOption):     _order(),
-	_key(data.GlobalRuleResourceType, data.UpdateOption):     _order(),
-	_key(data.PluginMetadataResourceType, data.DeleteOption): _order(),
-	_key(data.PluginMetadataResourceType, data.CreateOption): _order(),
-	_key(data.PluginMetadataResourceType, data.UpdateOption): _order(),
-}
-
 // Differ is the object of comparing two configurations.
 type Differ struct {
 	localDB      *db.DB
 	localConfig  *types.Configuration
 	remoteConfig *types.Configuration
+
+	// ownerLabels enables the ownership mode if it's not empty,
+	// only the remote resources with all the owner labels are deleted
+	ownerLabels types.Labels
+	unmanaged   []*data.Event
+
+	// resourceTypes selects the resource types to compare, nil for all
+	resourceTypes *data.ResourceTypeFilter
+
+	// ignoreRules are the fields excluded from the comparison
+	ignoreRules []*ignoreRule
 }
 
 // NewDiffer creates a new Differ object.
@@ -70,40 +33,133 @@ func NewDiffer(local, remote *types.Configuration) (*Differ, error) {
 		return nil, err
 	}
 
+	var rules []*ignoreRule
+	for _, field := range local.IgnoreFields {
+		rule, err := parseIgnoreRule(field)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
 	return &Differ{
 		localDB:      db,
 		localConfig:  local,
 		remoteConfig: remote,
+		ignoreRules:  rules,
 	}, nil
 }
 
-// sortEvents sorts events descending, higher priority events will be executed first
-func sortEvents(events []*data.Event) {
-	sort.Slice(events, func(i, j int) bool {
-		return order[_key(events[i].ResourceType, events[i].Option)] > order[_key(events[j].ResourceType, events[j].Option)]
-	})
+// SetResourceTypeFilter limits the resource types to compare,
+// no events are generated for the other resource types.
+func (d *Differ) SetResourceTypeFilter(filter *data.ResourceTypeFilter) {
+	d.resourceTypes = filter
+}
+
+// filterResourceTypes removes the events of resource types which aren't selected.
+func (d *Differ) filterResourceTypes(events []*data.Event) []*data.Event {
+	if d.resourceTypes == nil {
+		return events
+	}
+
+	var filtered []*data.Event
+	for _, event := range events {
+		if d.resourceTypes.Enabled(event.ResourceType) {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// SetOwnerLabels enables the ownership mode, in which only the remote resources
+// carrying all the owner labels are deleted.
+func (d *Differ) SetOwnerLabels(labels types.Labels) {
+	d.ownerLabels = labels
+}
+
+// Unmanaged returns the delete events skipped in the ownership mode by the
+// last Diff, the remote resources of them aren't owned by ADC.
+func (d *Differ) Unmanaged() []*data.Event {
+	return d.unmanaged
+}
+
+// skipUnmanaged removes the delete events of resources without the owner labels.
+func (d *Differ) skipUnmanaged(events []*data.Event) []*data.Event {
+	d.unmanaged = nil
+	if len(d.ownerLabels) == 0 {
+		return events
+	}
+
+	var managed []*data.Event
+	for _, event := range events {
+		if event.Option == data.DeleteOption && !d.isOwned(event.OldValue) {
+			d.unmanaged = append(d.unmanaged, event)
+			continue
+		}
+		managed = append(managed, event)
+	}
+	return managed
+}
+
+func (d *Differ) isOwned(resource interface{}) bool {
+	labels := reflect.Indirect(reflect.ValueOf(resource)).FieldByName("Labels")
+	if !labels.IsValid() {
+		return false
+	}
+
+	owned := labels.Interface().(types.Labels)
+	for key, value := range d.ownerLabels {
+		if v, ok := owned[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// sortEvents sorts events by their dependencies, the events that others
+// depend on will be executed first
+func sortEvents(events []*data.Event) ([]*data.Event, error) {
+	return data.NewGraph(events).Sort()
 }
 
 // Diff compares the local configuration and remote configuration, and returns the events.
 func (d *Differ) Diff() ([]*data.Event, error) {
 	var events []*data.Event
-	var err error
+	err := d.ignoreFields()
+	if err != nil {
+		return nil, err
+	}
 
 	serviceEvents, err := d.diffServices()
 	if err != nil {
 		return nil, err
 	}
 
+	upstreamEvents, err := d.diffUpstreams()
+	if err != nil {
+		return nil, err
+	}
+
 	routeEvents, err := d.diffRoutes()
 	if err != nil {
 		return nil, err
 	}
 
+	streamRouteEvents, err := d.diffStreamRoutes()
+	if err != nil {
+		return nil, err
+	}
+
 	consumerEvents, err := d.diffConsumers()
 	if err != nil {
 		return nil, err
 	}
 
+	credentialEvents, err := d.diffCredentials()
+	if err != nil {
+		return nil, err
+	}
+
 	sslEvents, err := d.diffSSLs()
 	if err != nil {
 		return nil, err
@@ -129,18 +185,32 @@ func (d *Differ) Diff() ([]*data.Event, error) {
 		return nil, err
 	}
 
+	protoEvents, err := d.diffProtos()
+	if err != nil {
+		return nil, err
+	}
+
+	secretEvents, err := d.diffSecrets()
+	if err != nil {
+		return nil, err
+	}
+
 	events = append(events, serviceEvents...)
+	events = append(events, upstreamEvents...)
 	events = append(events, routeEvents...)
+	events = append(events, streamRouteEvents...)
 	events = append(events, consumerEvents...)
+	events = append(events, credentialEvents...)
 	events = append(events, sslEvents...)
 	events = append(events, globalRuleEvents...)
 	events = append(events, pluginConfigEvents...)
 	events = append(events, pluginMetadataEvents...)
 	events = append(events, consumerGroupEvents...)
+	events = append(events, protoEvents...)
+	events = append(events, secretEvents...)
 
-	sortEvents(events)
-
-	return events, nil
+	events = d.filterResourceTypes(events)
+	return sortEvents(d.skipUnmanaged(events))
 }
 
 // diffService compares the services between local and remote.
@@ -169,7 +239,11 @@ func (d *Differ) diffServices() ([]*data.Event, error) {
 		mark[localSvc.ID] = true
 		// If the service is equal, we don't need to add an event.
 		// Else, we use the local service to update the remote service.
-		if equal := reflect.DeepEqual(localSvc, remoteSvc); equal {
+		equal, err := normalizedEqual(data.ServiceResourceType, localSvc, remoteSvc)
+		if err != nil {
+			return nil, err
+		}
+		if equal {
 			continue
 		}
 
@@ -197,6 +271,64 @@ func (d *Differ) diffServices() ([]*data.Event, error) {
 	return events, nil
 }
 
+// diffUpstreams compares the upstreams between local and remote.
+func (d *Differ) diffUpstreams() ([]*data.Event, error) {
+	var events []*data.Event
+	var mark = make(map[string]bool)
+
+	for _, remoteUpstream := range d.remoteConfig.Upstreams {
+		localUpstream, err := d.localDB.GetUpstreamByID(remoteUpstream.ID)
+		if err != nil {
+			// If we can't find the upstream in local, it means the upstream should be deleted.
+			// So we add a delete event and the value is the upstream from remote.
+			if err == db.NotFound {
+				e := data.Event{
+					ResourceType: data.UpstreamResourceType,
+					Option:       data.DeleteOption,
+					OldValue:     remoteUpstream,
+				}
+				events = append(events, &e)
+				continue
+			}
+
+			return nil, err
+		}
+
+		mark[localUpstream.ID] = true
+		// If the upstream is equal, we don't need to add an event.
+		// Else, we use the local upstream to update the remote upstream.
+		equal, err := normalizedEqual(data.UpstreamResourceType, localUpstream, remoteUpstream)
+		if err != nil {
+			return nil, err
+		}
+		if equal {
+			continue
+		}
+
+		events = append(events, &data.Event{
+			ResourceType: data.UpstreamResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     remoteUpstream,
+			Value:        localUpstream,
+		})
+	}
+
+	// If the upstream is not in the remote configuration, it means the upstream should be created.
+	for _, upstream := range d.localConfig.Upstreams {
+		if mark[upstream.ID] {
+			continue
+		}
+
+		events = append(events, &data.Event{
+			ResourceType: data.UpstreamResourceType,
+			Option:       data.CreateOption,
+			Value:        upstream,
+		})
+	}
+
+	return events, nil
+}
+
 // diffRoutes compares the routes between local and remote.
 func (d *Differ) diffRoutes() ([]*data.Event, error) {
 	var events []*data.Event
@@ -223,7 +355,11 @@ func (d *Differ) diffRoutes() ([]*data.Event, error) {
 		mark[localRoute.ID] = true
 		// If the route is equal, we don't need to add an event.
 		// Else, we use the local routes to update the remote routes.
-		if equal := reflect.DeepEqual(localRoute, remoteRoute); equal {
+		equal, err := normalizedEqual(data.RouteResourceType, localRoute, remoteRoute)
+		if err != nil {
+			return nil, err
+		}
+		if equal {
 			continue
 		}
 
@@ -251,6 +387,59 @@ func (d *Differ) diffRoutes() ([]*data.Event, error) {
 	return events, nil
 }
 
+// diffStreamRoutes compares the stream routes between local and remote.
+func (d *Differ) diffStreamRoutes() ([]*data.Event, error) {
+	var events []*data.Event
+	var mark = make(map[string]bool)
+
+	for _, remoteStreamRoute := range d.remoteConfig.StreamRoutes {
+		localStreamRoute, err := d.localDB.GetStreamRouteByID(remoteStreamRoute.ID)
+		if err != nil {
+			// we can't find in local config, should delete it
+			if err == db.NotFound {
+				e := data.Event{
+					ResourceType: data.StreamRouteResourceType,
+					Option:       data.DeleteOption,
+					OldValue:     remoteStreamRoute,
+				}
+				events = append(events, &e)
+				continue
+			}
+
+			return nil, err
+		}
+
+		mark[localStreamRoute.ID] = true
+		// skip when equals
+		if equal := reflect.DeepEqual(localStreamRoute, remoteStreamRoute); equal {
+			continue
+		}
+
+		// otherwise update
+		events = append(events, &data.Event{
+			ResourceType: data.StreamRouteResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     remoteStreamRoute,
+			Value:        localStreamRoute,
+		})
+	}
+
+	// only in local, create
+	for _, streamRoute := range d.localConfig.StreamRoutes {
+		if mark[streamRoute.ID] {
+			continue
+		}
+
+		events = append(events, &data.Event{
+			ResourceType: data.StreamRouteResourceType,
+			Option:       data.CreateOption,
+			Value:        streamRoute,
+		})
+	}
+
+	return events, nil
+}
+
 // diffConsumers compares the consumers between local and remote.
 func (d *Differ) diffConsumers() ([]*data.Event, error) {
 	var events []*data.Event
@@ -274,8 +463,10 @@ func (d *Differ) diffConsumers() ([]*data.Event, error) {
 		}
 
 		mark[localConsumer.Username] = true
-		// skip when equals
-		if equal := reflect.DeepEqual(localConsumer, remoteConsumers); equal {
+		// skip when equals, the credentials are compared separately
+		remoteConsumer := remoteConsumers.WithoutCredentials()
+		localConsumer = localConsumer.WithoutCredentials()
+		if equal := reflect.DeepEqual(localConsumer, remoteConsumer); equal {
 			continue
 		}
 
@@ -283,7 +474,7 @@ func (d *Differ) diffConsumers() ([]*data.Event, error) {
 		events = append(events, &data.Event{
 			ResourceType: data.ConsumerResourceType,
 			Option:       data.UpdateOption,
-			OldValue:     remoteConsumers,
+			OldValue:     remoteConsumer,
 			Value:        localConsumer,
 		})
 	}
@@ -297,13 +488,70 @@ func (d *Differ) diffConsumers() ([]*data.Event, error) {
 		events = append(events, &data.Event{
 			ResourceType: data.ConsumerResourceType,
 			Option:       data.CreateOption,
-			Value:        consumer,
+			Value:        consumer.WithoutCredentials(),
 		})
 	}
 
 	return events, nil
 }
 
+// diffCredentials compares the consumer credentials between local and remote.
+func (d *Differ) diffCredentials() ([]*data.Event, error) {
+	var events []*data.Event
+	var mark = make(map[string]bool)
+
+	for _, remoteConsumer := range d.remoteConfig.Consumers {
+		for _, remoteCredential := range remoteConsumer.Credentials {
+			localCredential, err := d.localDB.GetCredentialByID(remoteCredential.Consumer, remoteCredential.ID)
+			if err != nil {
+				// we can't find in local config, should delete it
+				if err == db.NotFound {
+					e := data.Event{
+						ResourceType: data.CredentialResourceType,
+						Option:       data.DeleteOption,
+						OldValue:     remoteCredential,
+					}
+					events = append(events, &e)
+					continue
+				}
+
+				return nil, err
+			}
+
+			mark[localCredential.ResourceKey()] = true
+			// skip when equals
+			if equal := reflect.DeepEqual(localCredential, remoteCredential); equal {
+				continue
+			}
+
+			// otherwise update
+			events = append(events, &data.Event{
+				ResourceType: data.CredentialResourceType,
+				Option:       data.UpdateOption,
+				OldValue:     remoteCredential,
+				Value:        localCredential,
+			})
+		}
+	}
+
+	// only in local, create
+	for _, consumer := range d.localConfig.Consumers {
+		for _, credential := range consumer.Credentials {
+			if mark[credential.ResourceKey()] {
+				continue
+			}
+
+			events = append(events, &data.Event{
+				ResourceType: data.CredentialResourceType,
+				Option:       data.CreateOption,
+				Value:        credential,
+			})
+		}
+	}
+
+	return events, nil
+}
+
 // diffSSLs compares the routes between local and remote.
 func (d *Differ) diffSSLs() ([]*data.Event, error) {
 	var events []*data.Event
@@ -328,7 +576,11 @@ func (d *Differ) diffSSLs() ([]*data.Event, error) {
 
 		mark[localSSL.ID] = true
 		// skip when equals
-		if equal := reflect.DeepEqual(localSSL, remoteSSL); equal {
+		equal, err := normalizedEqual(data.SSLResourceType, localSSL, remoteSSL)
+		if err != nil {
+			return nil, err
+		}
+		if equal {
 			continue
 		}
 
@@ -567,3 +819,127 @@ func (d *Differ) diffPluginMetadata() ([]*data.Event, error) {
 
 	return events, nil
 }
+
+// diffProtos compares the protos between local and remote.
+func (d *Differ) diffProtos() ([]*data.Event, error) {
+	var events []*data.Event
+	var mark = make(map[string]bool)
+
+	for _, remoteProto := range d.remoteConfig.Protos {
+		localProto, err := d.localDB.GetProtoByID(remoteProto.ID)
+		if err != nil {
+			// we can't find in local config, should delete it
+			if err == db.NotFound {
+				e := data.Event{
+					ResourceType: data.ProtoResourceType,
+					Option:       data.DeleteOption,
+					OldValue:     remoteProto,
+				}
+				events = append(events, &e)
+				continue
+			}
+
+			return nil, err
+		}
+
+		mark[localProto.ID] = true
+		// skip when equals
+		if equal := reflect.DeepEqual(localProto, remoteProto); equal {
+			continue
+		}
+
+		// otherwise update
+		events = append(events, &data.Event{
+			ResourceType: data.ProtoResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     remoteProto,
+			Value:        localProto,
+		})
+	}
+
+	// only in local, create
+	for _, proto := range d.localConfig.Protos {
+		if mark[proto.ID] {
+			continue
+		}
+
+		events = append(events, &data.Event{
+			ResourceType: data.ProtoResourceType,
+			Option:       data.CreateOption,
+			Value:        proto,
+		})
+	}
+
+	return events, nil
+}
+
+// diffSecrets compares the secrets between local and remote.
+func (d *Differ) diffSecrets() ([]*data.Event, error) {
+	var events []*data.Event
+	var mark = make(map[string]bool)
+
+	for _, remoteSecret := range d.remoteConfig.Secrets {
+		localSecret, err := d.localDB.GetSecretByID(remoteSecret.Manager, remoteSecret.ID)
+		if err != nil {
+			// we can't find in local config, should delete it
+			if err == db.NotFound {
+				e := data.Event{
+					ResourceType: data.SecretResourceType,
+					Option:       data.DeleteOption,
+					OldValue:     remoteSecret,
+				}
+				events = append(events, &e)
+				continue
+			}
+
+			return nil, err
+		}
+
+		mark[localSecret.ResourceKey()] = true
+		// skip when equals
+		if equal := reflect.DeepEqual(localSecret, remoteSecret); equal {
+			continue
+		}
+
+		// otherwise update
+		events = append(events, &data.Event{
+			ResourceType: data.SecretResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     remoteSecret,
+			Value:        localSecret,
+		})
+	}
+
+	// only in local, create
+	for _, secret := range d.localConfig.Secrets {
+		if mark[secret.ResourceKey()] {
+			continue
+		}
+
+		events = append(events, &data.Event{
+			ResourceType: data.SecretResourceType,
+			Option:       data.CreateOption,
+			Value:        secret,
+		})
+	}
+
+	return events, nil
+}
+
+// normalizedEqual compares the resources with the server-side default values
+// filled in, so that the defaults APISIX injects don't make a difference.
+func normalizedEqual[T any](typ data.ResourceType, local, remote *T) (bool, error) {
+	if reflect.DeepEqual(local, remote) {
+		return true, nil
+	}
+
+	normalizedLocal, err := types.Normalize(string(typ), local)
+	if err != nil {
+		return false, err
+	}
+	normalizedRemote, err := types.Normalize(string(typ), remote)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(normalizedLocal, normalizedRemote), nil
+}
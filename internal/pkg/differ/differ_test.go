@@ -31,6 +31,24 @@ var (
 		},
 	}
 
+	upstream = &types.Upstream{
+		ID:   "upstream",
+		Name: "upstream",
+		Nodes: []types.UpstreamNode{
+			{
+				Host:   "httpbin.org",
+				Port:   80,
+				Weight: 1,
+			},
+		},
+	}
+
+	streamRoute = &types.StreamRoute{
+		ID:         "stream-route",
+		ServerPort: 9100,
+		UpstreamId: "upstream",
+	}
+
 	route = &types.Route{
 		ID:   "route",
 		Name: "route",
@@ -45,63 +63,164 @@ var (
 )
 
 func TestSortEvents(t *testing.T) {
+	createdRoute := &types.Route{ID: "route1", ServiceID: "svc1"}
+	createdSvc := &types.Service{ID: "svc1"}
+	deletedRoute := &types.Route{ID: "route2", ServiceID: "svc2"}
+	deletedSvc := &types.Service{ID: "svc2"}
+	updatedRoute := &types.Route{ID: "route3", ServiceID: "svc3"}
+	updatedSvc := &types.Service{ID: "svc3"}
+
 	events := []*data.Event{
 		{
 			ResourceType: data.RouteResourceType,
 			Option:       data.CreateOption,
+			Value:        createdRoute,
 		},
 		{
 			ResourceType: data.ServiceResourceType,
 			Option:       data.CreateOption,
+			Value:        createdSvc,
 		},
 		{
 			ResourceType: data.RouteResourceType,
 			Option:       data.DeleteOption,
+			OldValue:     deletedRoute,
 		},
 		{
 			ResourceType: data.ServiceResourceType,
 			Option:       data.DeleteOption,
+			OldValue:     deletedSvc,
 		},
 		{
 			ResourceType: data.RouteResourceType,
 			Option:       data.UpdateOption,
+			OldValue:     updatedRoute,
+			Value:        updatedRoute,
 		},
 		{
 			ResourceType: data.ServiceResourceType,
 			Option:       data.UpdateOption,
+			OldValue:     updatedSvc,
+			Value:        updatedSvc,
 		},
 	}
 
-	sortEvents(events)
+	events, err := sortEvents(events)
+	assert.Nil(t, err, "check the error")
 	assert.Equal(t, []*data.Event{
 		{
 			ResourceType: data.ServiceResourceType,
 			Option:       data.CreateOption,
+			Value:        createdSvc,
 		},
 		{
 			ResourceType: data.RouteResourceType,
 			Option:       data.CreateOption,
+			Value:        createdRoute,
 		},
 		{
 			ResourceType: data.ServiceResourceType,
 			Option:       data.UpdateOption,
+			OldValue:     updatedSvc,
+			Value:        updatedSvc,
 		},
 		{
 			ResourceType: data.RouteResourceType,
 			Option:       data.UpdateOption,
+			OldValue:     updatedRoute,
+			Value:        updatedRoute,
 		},
 		{
 			ResourceType: data.RouteResourceType,
 			Option:       data.DeleteOption,
+			OldValue:     deletedRoute,
 		},
 		{
 			ResourceType: data.ServiceResourceType,
 			Option:       data.DeleteOption,
+			OldValue:     deletedSvc,
 		},
 	}, events, "check the content of sorted events")
 
 }
 
+func TestSortUpstreamEvents(t *testing.T) {
+	deletedRoute := &types.Route{ID: "route1", ServiceID: "svc1"}
+	deletedUpstream := &types.Upstream{ID: "upstream1"}
+	deletedSvc := &types.Service{ID: "svc1", UpstreamId: "upstream1"}
+	createdRoute := &types.Route{ID: "route2", ServiceID: "svc2"}
+	createdSvc := &types.Service{ID: "svc2", UpstreamId: "upstream2"}
+	createdUpstream := &types.Upstream{ID: "upstream2"}
+
+	events := []*data.Event{
+		{
+			ResourceType: data.RouteResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     deletedRoute,
+		},
+		{
+			ResourceType: data.UpstreamResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     deletedUpstream,
+		},
+		{
+			ResourceType: data.ServiceResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     deletedSvc,
+		},
+		{
+			ResourceType: data.RouteResourceType,
+			Option:       data.CreateOption,
+			Value:        createdRoute,
+		},
+		{
+			ResourceType: data.ServiceResourceType,
+			Option:       data.CreateOption,
+			Value:        createdSvc,
+		},
+		{
+			ResourceType: data.UpstreamResourceType,
+			Option:       data.CreateOption,
+			Value:        createdUpstream,
+		},
+	}
+
+	events, err := sortEvents(events)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.UpstreamResourceType,
+			Option:       data.CreateOption,
+			Value:        createdUpstream,
+		},
+		{
+			ResourceType: data.ServiceResourceType,
+			Option:       data.CreateOption,
+			Value:        createdSvc,
+		},
+		{
+			ResourceType: data.RouteResourceType,
+			Option:       data.CreateOption,
+			Value:        createdRoute,
+		},
+		{
+			ResourceType: data.RouteResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     deletedRoute,
+		},
+		{
+			ResourceType: data.ServiceResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     deletedSvc,
+		},
+		{
+			ResourceType: data.UpstreamResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     deletedUpstream,
+		},
+	}, events, "check the content of sorted events")
+}
+
 func TestDiff(t *testing.T) {
 	// Test case 1: delete events
 	localConfig := &types.Configuration{
@@ -139,6 +258,84 @@ func TestDiff(t *testing.T) {
 	}, events, "check the content of delete events")
 }
 
+func TestDiffOwnership(t *testing.T) {
+	ownedRoute := *route
+	ownedRoute.Labels = types.Labels{"managed-by": "adc"}
+	globalRule := &types.GlobalRule{ID: "rule"}
+	remoteConfig := &types.Configuration{
+		Services:    []*types.Service{svc},
+		Routes:      []*types.Route{&ownedRoute},
+		GlobalRules: []*types.GlobalRule{globalRule},
+	}
+
+	// Test case 1: all remote resources are deleted without ownership
+	differ, _ := NewDiffer(&types.Configuration{}, remoteConfig)
+	events, _ := differ.Diff()
+	assert.Equal(t, 3, len(events), "check the number of delete events")
+	assert.Nil(t, differ.Unmanaged(), "check the unmanaged events")
+
+	// Test case 2: only the owned resources are deleted
+	differ.SetOwnerLabels(types.Labels{"managed-by": "adc"})
+	events, _ = differ.Diff()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.RouteResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     &ownedRoute,
+		},
+	}, events, "check the content of delete events")
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.ServiceResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     svc,
+		},
+		{
+			ResourceType: data.GlobalRuleResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     globalRule,
+		},
+	}, differ.Unmanaged(), "check the unmanaged events")
+}
+
+func TestDiffResourceTypeFilter(t *testing.T) {
+	localConfig := &types.Configuration{
+		Consumers: []*types.Consumer{
+			{
+				Username: "jack",
+				Credentials: []*types.Credential{
+					{
+						ID:       "cred",
+						Consumer: "jack",
+					},
+				},
+			},
+		},
+	}
+	remoteConfig := &types.Configuration{
+		Services: []*types.Service{svc},
+		Routes:   []*types.Route{route},
+	}
+
+	// Test case 1: the excluded types don't produce events
+	filter, _ := data.NewResourceTypeFilter(nil, []string{"service", "consumer"})
+	differ, _ := NewDiffer(localConfig, remoteConfig)
+	differ.SetResourceTypeFilter(filter)
+	events, _ := differ.Diff()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.CredentialResourceType,
+			Option:       data.CreateOption,
+			Value:        localConfig.Consumers[0].Credentials[0],
+		},
+		{
+			ResourceType: data.RouteResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     route,
+		},
+	}, events, "check the content of events")
+}
+
 func TestDiffServices(t *testing.T) {
 	// Test case 1: delete events
 	localConfig := &types.Configuration{
@@ -344,3 +541,462 @@ func TestDifferRoutes(t *testing.T) {
 		},
 	}, events, "check the content of delete and create events")
 }
+
+func TestDiffUpstreams(t *testing.T) {
+	// Test case 1: delete events
+	localConfig := &types.Configuration{
+		Upstreams: []*types.Upstream{},
+	}
+	remoteConfig := &types.Configuration{
+		Upstreams: []*types.Upstream{upstream},
+	}
+
+	differ, _ := NewDiffer(localConfig, remoteConfig)
+	events, _ := differ.diffUpstreams()
+	assert.Equal(t, 1, len(events), "check the number of delete events")
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.UpstreamResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     upstream,
+		},
+	}, events, "check the content of delete events")
+
+	// Test case 2: update events
+	localConfig = &types.Configuration{
+		Upstreams: []*types.Upstream{upstream},
+	}
+	upstream1 := *upstream
+	upstream1.Type = "chash"
+	remoteConfig = &types.Configuration{
+		Upstreams: []*types.Upstream{&upstream1},
+	}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffUpstreams()
+	assert.Equal(t, 1, len(events), "check the number of update events")
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.UpstreamResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     &upstream1,
+			Value:        upstream,
+		},
+	}, events, "check the content of update events")
+
+	// Test case 3: create events
+	localConfig = &types.Configuration{
+		Upstreams: []*types.Upstream{upstream},
+	}
+	remoteConfig = &types.Configuration{
+		Upstreams: []*types.Upstream{},
+	}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffUpstreams()
+	assert.Equal(t, 1, len(events), "check the number of create events")
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.UpstreamResourceType,
+			Option:       data.CreateOption,
+			Value:        upstream,
+		},
+	}, events, "check the content of create events")
+
+	// Test case 4: no events
+	localConfig = &types.Configuration{
+		Upstreams: []*types.Upstream{upstream},
+	}
+	remoteConfig = &types.Configuration{
+		Upstreams: []*types.Upstream{upstream},
+	}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffUpstreams()
+	assert.Equal(t, 0, len(events), "check the number of no events")
+
+	// Test case 5: no events for the default values filled in by APISIX
+	upstream2 := *upstream
+	upstream2.Type = "roundrobin"
+	upstream2.HashOn = "vars"
+	upstream2.Scheme = "http"
+	upstream2.PassHost = "pass"
+	localUpstream := *upstream
+	localUpstream.Type = ""
+	localUpstream.HashOn = ""
+	localUpstream.Scheme = ""
+	localUpstream.PassHost = ""
+	localConfig = &types.Configuration{
+		Upstreams: []*types.Upstream{&localUpstream},
+	}
+	remoteConfig = &types.Configuration{
+		Upstreams: []*types.Upstream{&upstream2},
+	}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffUpstreams()
+	assert.Equal(t, 0, len(events), "check the number of no events")
+}
+
+func TestDiffStreamRoutes(t *testing.T) {
+	// Test case 1: delete events
+	localConfig := &types.Configuration{
+		StreamRoutes: []*types.StreamRoute{},
+	}
+	remoteConfig := &types.Configuration{
+		StreamRoutes: []*types.StreamRoute{streamRoute},
+	}
+
+	differ, _ := NewDiffer(localConfig, remoteConfig)
+	events, _ := differ.diffStreamRoutes()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.StreamRouteResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     streamRoute,
+		},
+	}, events, "check the content of delete events")
+
+	// Test case 2: update events
+	localConfig = &types.Configuration{
+		StreamRoutes: []*types.StreamRoute{streamRoute},
+	}
+	streamRoute1 := *streamRoute
+	streamRoute1.ServerPort = 9101
+	remoteConfig = &types.Configuration{
+		StreamRoutes: []*types.StreamRoute{&streamRoute1},
+	}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffStreamRoutes()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.StreamRouteResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     &streamRoute1,
+			Value:        streamRoute,
+		},
+	}, events, "check the content of update events")
+
+	// Test case 3: create events
+	localConfig = &types.Configuration{
+		StreamRoutes: []*types.StreamRoute{streamRoute},
+	}
+	remoteConfig = &types.Configuration{}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffStreamRoutes()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.StreamRouteResourceType,
+			Option:       data.CreateOption,
+			Value:        streamRoute,
+		},
+	}, events, "check the content of create events")
+}
+
+func TestDiffProtos(t *testing.T) {
+	proto := &types.Proto{
+		ID:      "helloworld",
+		Content: `syntax = "proto3"; package helloworld;`,
+	}
+
+	// Test case 1: delete events
+	localConfig := &types.Configuration{
+		Protos: []*types.Proto{},
+	}
+	remoteConfig := &types.Configuration{
+		Protos: []*types.Proto{proto},
+	}
+
+	differ, _ := NewDiffer(localConfig, remoteConfig)
+	events, _ := differ.diffProtos()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.ProtoResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     proto,
+		},
+	}, events, "check the content of delete events")
+
+	// Test case 2: update events
+	localConfig = &types.Configuration{
+		Protos: []*types.Proto{proto},
+	}
+	proto1 := *proto
+	proto1.Content = `syntax = "proto3"; package greeter;`
+	remoteConfig = &types.Configuration{
+		Protos: []*types.Proto{&proto1},
+	}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffProtos()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.ProtoResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     &proto1,
+			Value:        proto,
+		},
+	}, events, "check the content of update events")
+
+	// Test case 3: create events
+	remoteConfig = &types.Configuration{}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffProtos()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.ProtoResourceType,
+			Option:       data.CreateOption,
+			Value:        proto,
+		},
+	}, events, "check the content of create events")
+
+	// Test case 4: no events if the protos are equal
+	proto2 := *proto
+	remoteConfig = &types.Configuration{
+		Protos: []*types.Proto{&proto2},
+	}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffProtos()
+	assert.Empty(t, events, "check there are no events")
+}
+
+func TestDiffSecrets(t *testing.T) {
+	secret := &types.Secret{
+		ID:      "1",
+		Manager: "vault",
+		Config: map[string]interface{}{
+			"uri":    "http://127.0.0.1:8200",
+			"prefix": "kv/apisix",
+			"token":  "root",
+		},
+	}
+
+	// Test case 1: the same ID with different managers
+	secret1 := *secret
+	secret1.Manager = "aws"
+	localConfig := &types.Configuration{
+		Secrets: []*types.Secret{secret},
+	}
+	remoteConfig := &types.Configuration{
+		Secrets: []*types.Secret{&secret1},
+	}
+
+	differ, _ := NewDiffer(localConfig, remoteConfig)
+	events, _ := differ.diffSecrets()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.SecretResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     &secret1,
+		},
+		{
+			ResourceType: data.SecretResourceType,
+			Option:       data.CreateOption,
+			Value:        secret,
+		},
+	}, events, "check the content of delete and create events")
+
+	// Test case 2: update events
+	secret2 := *secret
+	secret2.Config = map[string]interface{}{
+		"uri":    "http://127.0.0.1:8200",
+		"prefix": "kv/apisix",
+		"token":  "changed",
+	}
+	remoteConfig = &types.Configuration{
+		Secrets: []*types.Secret{&secret2},
+	}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffSecrets()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.SecretResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     &secret2,
+			Value:        secret,
+		},
+	}, events, "check the content of update events")
+
+	// Test case 3: secrets are created first and deleted last
+	events = []*data.Event{
+		{
+			ResourceType: data.SecretResourceType,
+			Option:       data.DeleteOption,
+		},
+		{
+			ResourceType: data.SSLResourceType,
+			Option:       data.CreateOption,
+		},
+		{
+			ResourceType: data.RouteResourceType,
+			Option:       data.DeleteOption,
+		},
+		{
+			ResourceType: data.SecretResourceType,
+			Option:       data.CreateOption,
+		},
+	}
+	events, _ = sortEvents(events)
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.SecretResourceType,
+			Option:       data.CreateOption,
+		},
+		{
+			ResourceType: data.SSLResourceType,
+			Option:       data.CreateOption,
+		},
+		{
+			ResourceType: data.RouteResourceType,
+			Option:       data.DeleteOption,
+		},
+		{
+			ResourceType: data.SecretResourceType,
+			Option:       data.DeleteOption,
+		},
+	}, events, "check the content of sorted events")
+}
+
+func TestDiffCredentials(t *testing.T) {
+	newConsumer := func(key string) *types.Consumer {
+		return &types.Consumer{
+			Username: "jack",
+			Credentials: []*types.Credential{
+				{
+					ID:       "key-1",
+					Consumer: "jack",
+					Plugins: types.Plugins{
+						"key-auth": types.Plugin{
+							"key": key,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// Test case 1: rotating a key only updates the credential
+	local := newConsumer("new-key")
+	remote := newConsumer("old-key")
+	localConfig := &types.Configuration{
+		Consumers: []*types.Consumer{local},
+	}
+	remoteConfig := &types.Configuration{
+		Consumers: []*types.Consumer{remote},
+	}
+
+	differ, _ := NewDiffer(localConfig, remoteConfig)
+	events, err := differ.Diff()
+	assert.Nil(t, err)
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.CredentialResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     remote.Credentials[0],
+			Value:        local.Credentials[0],
+		},
+	}, events, "check the content of update events")
+
+	// Test case 2: credentials are created after the consumer
+	localConfig = &types.Configuration{
+		Consumers: []*types.Consumer{local},
+	}
+	remoteConfig = &types.Configuration{}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, err = differ.Diff()
+	assert.Nil(t, err)
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.ConsumerResourceType,
+			Option:       data.CreateOption,
+			Value: &types.Consumer{
+				Username: "jack",
+			},
+		},
+		{
+			ResourceType: data.CredentialResourceType,
+			Option:       data.CreateOption,
+			Value:        local.Credentials[0],
+		},
+	}, events, "check the content of create events")
+
+	// Test case 3: credentials are deleted before the consumer
+	localConfig = &types.Configuration{}
+	remoteConfig = &types.Configuration{
+		Consumers: []*types.Consumer{remote},
+	}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, err = differ.Diff()
+	assert.Nil(t, err)
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.CredentialResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     remote.Credentials[0],
+		},
+		{
+			ResourceType: data.ConsumerResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     remote,
+		},
+	}, events, "check the content of delete events")
+}
+
+func TestDiffSSLs(t *testing.T) {
+	ssl := &types.SSL{
+		ID:   "ssl",
+		SNIs: []string{"foo.com"},
+		Cert: "cert",
+		Key:  "key",
+		Client: &types.SSLClient{
+			CA:    "ca",
+			Depth: 1,
+		},
+		SSLProtocols: []string{"TLSv1.2", "TLSv1.3"},
+	}
+
+	// Test case 1: client verification depth is changed
+	ssl1 := *ssl
+	ssl1.Client = &types.SSLClient{
+		CA:    "ca",
+		Depth: 2,
+	}
+	localConfig := &types.Configuration{
+		SSLs: []*types.SSL{ssl},
+	}
+	remoteConfig := &types.Configuration{
+		SSLs: []*types.SSL{&ssl1},
+	}
+
+	differ, _ := NewDiffer(localConfig, remoteConfig)
+	events, _ := differ.diffSSLs()
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.SSLResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     &ssl1,
+			Value:        ssl,
+		},
+	}, events, "check the content of update events")
+
+	// Test case 2: no events
+	ssl2 := *ssl
+	ssl2.Client = &types.SSLClient{
+		CA:    "ca",
+		Depth: 1,
+	}
+	remoteConfig = &types.Configuration{
+		SSLs: []*types.SSL{&ssl2},
+	}
+
+	differ, _ = NewDiffer(localConfig, remoteConfig)
+	events, _ = differ.diffSSLs()
+	assert.Equal(t, 0, len(events), "check the number of no events")
+}
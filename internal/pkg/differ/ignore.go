@@ -0,0 +1,257 @@
+package differ
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/api7/adc/pkg/api/apisix"
+	"github.com/api7/adc/pkg/api/apisix/types"
+	"github.com/api7/adc/pkg/data"
+)
+
+// wildcard matches any object key, resource ID or array index in ignore rules
+const wildcard = "*"
+
+// ignoreRule is a parsed ignore-field rule, e.g. service.*.upstream.nodes
+// is parsed into the resource type "service", the resource ID "*" and
+// the fields ["upstream", "nodes"]. Array indices are kept as "[0]" or "[*]".
+type ignoreRule struct {
+	resourceType data.ResourceType
+	id           string
+	fields       []string
+}
+
+// parseIgnoreRule parses the rule in the form of <resource type>.<id>.<field path>,
+// the keys which contain dots can be quoted, e.g. route["api.v1"].labels["app.kubernetes.io/name"].
+func parseIgnoreRule(rule string) (*ignoreRule, error) {
+	segments, err := splitPath(rule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ignore rule '%s': %s", rule, err.Error())
+	}
+	if len(segments) < 3 {
+		return nil, fmt.Errorf("invalid ignore rule '%s': it should be in the form of <resource type>.<id>.<field path>", rule)
+	}
+
+	typ := data.ResourceType(segments[0])
+	known := false
+	for _, t := range data.ResourceTypes {
+		known = known || t == typ
+	}
+	if !known {
+		return nil, fmt.Errorf("invalid ignore rule '%s': unknown resource type '%s'", rule, segments[0])
+	}
+
+	return &ignoreRule{
+		resourceType: typ,
+		id:           segments[1],
+		fields:       segments[2:],
+	}, nil
+}
+
+// splitPath splits the path into object keys and array indices,
+// e.g. a.b["c.d"][0] is split into ["a", "b", "c.d", "[0]"].
+func splitPath(path string) ([]string, error) {
+	var segments []string
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			if i == 0 || i == len(path)-1 {
+				return nil, fmt.Errorf("unexpected '.' at %d", i)
+			}
+			i++
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' at %d", i)
+			}
+			inner := path[i+1 : i+end]
+			if strings.HasPrefix(inner, "\"") {
+				key := ""
+				if _, err := fmt.Sscanf(inner, "%q", &key); err != nil {
+					return nil, fmt.Errorf("invalid quoted key %s", inner)
+				}
+				segments = append(segments, key)
+			} else {
+				segments = append(segments, "["+inner+"]")
+			}
+			i += end + 1
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			segments = append(segments, path[i:i+end])
+			i += end
+		}
+	}
+	return segments, nil
+}
+
+// ignoreFields copies the ignored fields from the remote resources to the local
+// ones, so that the ignored fields are neither compared nor updated.
+func (d *Differ) ignoreFields() error {
+	if len(d.ignoreRules) == 0 {
+		return nil
+	}
+
+	var localCredentials, remoteCredentials []*types.Credential
+	for _, consumer := range d.localConfig.Consumers {
+		localCredentials = append(localCredentials, consumer.Credentials...)
+	}
+	for _, consumer := range d.remoteConfig.Consumers {
+		remoteCredentials = append(remoteCredentials, consumer.Credentials...)
+	}
+
+	for _, err := range []error{
+		ignoreFields(d.ignoreRules, data.ServiceResourceType, d.localConfig.Services, d.remoteConfig.Services),
+		ignoreFields(d.ignoreRules, data.UpstreamResourceType, d.localConfig.Upstreams, d.remoteConfig.Upstreams),
+		ignoreFields(d.ignoreRules, data.RouteResourceType, d.localConfig.Routes, d.remoteConfig.Routes),
+		ignoreFields(d.ignoreRules, data.StreamRouteResourceType, d.localConfig.StreamRoutes, d.remoteConfig.StreamRoutes),
+		ignoreFields(d.ignoreRules, data.ConsumerResourceType, d.localConfig.Consumers, d.remoteConfig.Consumers),
+		ignoreFields(d.ignoreRules, data.CredentialResourceType, localCredentials, remoteCredentials),
+		ignoreFields(d.ignoreRules, data.SSLResourceType, d.localConfig.SSLs, d.remoteConfig.SSLs),
+		ignoreFields(d.ignoreRules, data.GlobalRuleResourceType, d.localConfig.GlobalRules, d.remoteConfig.GlobalRules),
+		ignoreFields(d.ignoreRules, data.PluginConfigResourceType, d.localConfig.PluginConfigs, d.remoteConfig.PluginConfigs),
+		ignoreFields(d.ignoreRules, data.ConsumerGroupResourceType, d.localConfig.ConsumerGroups, d.remoteConfig.ConsumerGroups),
+		ignoreFields(d.ignoreRules, data.PluginMetadataResourceType, d.localConfig.PluginMetadatas, d.remoteConfig.PluginMetadatas),
+		ignoreFields(d.ignoreRules, data.ProtoResourceType, d.localConfig.Protos, d.remoteConfig.Protos),
+		ignoreFields(d.ignoreRules, data.SecretResourceType, d.localConfig.Secrets, d.remoteConfig.Secrets),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ignoreFields updates the local resources in place, the local database
+// holds the same pointers so it sees the updates as well.
+func ignoreFields[T any](rules []*ignoreRule, typ data.ResourceType, locals, remotes []*T) error {
+	localByKey := make(map[string]*T, len(locals))
+	for _, local := range locals {
+		localByKey[apisix.GetResourceUniqueKey(local)] = local
+	}
+
+	for _, remote := range remotes {
+		key := apisix.GetResourceUniqueKey(remote)
+		local, ok := localByKey[key]
+		if !ok {
+			continue
+		}
+
+		var matched []*ignoreRule
+		for _, rule := range rules {
+			if rule.resourceType == typ && (rule.id == wildcard || rule.id == key) {
+				matched = append(matched, rule)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		localValue, err := toGeneric(local)
+		if err != nil {
+			return err
+		}
+		remoteValue, err := toGeneric(remote)
+		if err != nil {
+			return err
+		}
+		for _, rule := range matched {
+			localValue = copyFields(localValue, remoteValue, rule.fields)
+		}
+
+		content, err := json.Marshal(localValue)
+		if err != nil {
+			return err
+		}
+		var merged T
+		err = json.Unmarshal(content, &merged)
+		if err != nil {
+			return err
+		}
+
+		switch r := any(&merged).(type) {
+		case *types.Credential:
+			// the consumer of credential isn't a part of its JSON
+			r.Consumer = any(local).(*types.Credential).Consumer
+		case *types.Consumer:
+			// the credentials are compared separately, keep the same objects
+			r.Credentials = any(local).(*types.Consumer).Credentials
+		}
+		*local = merged
+	}
+
+	return nil
+}
+
+func toGeneric(value interface{}) (interface{}, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	err = json.Unmarshal(content, &generic)
+	return generic, err
+}
+
+// copyFields copies the fields matched by the path from remote to local,
+// the fields only in local are removed, and the local value is returned.
+func copyFields(local, remote interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return remote
+	}
+	segment, rest := path[0], path[1:]
+
+	if strings.HasPrefix(segment, "[") {
+		localSlice, ok := local.([]interface{})
+		if !ok {
+			return local
+		}
+		remoteSlice, ok := remote.([]interface{})
+		if !ok {
+			return local
+		}
+		for i := range localSlice {
+			if i < len(remoteSlice) && (segment == "["+wildcard+"]" || segment == fmt.Sprintf("[%d]", i)) {
+				localSlice[i] = copyFields(localSlice[i], remoteSlice[i], rest)
+			}
+		}
+		return localSlice
+	}
+
+	localMap, ok := local.(map[string]interface{})
+	if !ok {
+		return local
+	}
+	remoteMap, _ := remote.(map[string]interface{})
+
+	keys := []string{segment}
+	if segment == wildcard {
+		keys = nil
+		for key := range localMap {
+			keys = append(keys, key)
+		}
+		for key := range remoteMap {
+			if _, ok := localMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		localValue, inLocal := localMap[key]
+		remoteValue, inRemote := remoteMap[key]
+		switch {
+		case len(rest) == 0 && inRemote:
+			localMap[key] = remoteValue
+		case len(rest) == 0:
+			delete(localMap, key)
+		case inLocal && inRemote:
+			localMap[key] = copyFields(localValue, remoteValue, rest)
+		}
+	}
+	return localMap
+}
@@ -0,0 +1,142 @@
+package differ
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+	"github.com/api7/adc/pkg/data"
+)
+
+func TestParseIgnoreRule(t *testing.T) {
+	// Test case 1: wildcard ID
+	rule, err := parseIgnoreRule("service.*.upstream.nodes")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, &ignoreRule{
+		resourceType: data.ServiceResourceType,
+		id:           "*",
+		fields:       []string{"upstream", "nodes"},
+	}, rule, "check the rule")
+
+	// Test case 2: quoted keys and array indices
+	rule, err = parseIgnoreRule(`route["api.v1"].labels["app.kubernetes.io/name"].hosts[*]`)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, &ignoreRule{
+		resourceType: data.RouteResourceType,
+		id:           "api.v1",
+		fields:       []string{"labels", "app.kubernetes.io/name", "hosts", "[*]"},
+	}, rule, "check the rule")
+
+	// Test case 3: invalid rules
+	_, err = parseIgnoreRule("service.*")
+	assert.Equal(t, "invalid ignore rule 'service.*': it should be in the form of <resource type>.<id>.<field path>", err.Error(), "check the error")
+	_, err = parseIgnoreRule("services.*.name")
+	assert.Equal(t, "invalid ignore rule 'services.*.name': unknown resource type 'services'", err.Error(), "check the error")
+	_, err = parseIgnoreRule("service.*.labels[\"a\"")
+	assert.Equal(t, "invalid ignore rule 'service.*.labels[\"a\"': unclosed '[' at 16", err.Error(), "check the error")
+}
+
+func TestDiffIgnoreFields(t *testing.T) {
+	remoteService := &types.Service{
+		ID:   "svc",
+		Name: "svc",
+		Upstream: types.Upstream{
+			Nodes: types.UpstreamNodes{
+				{Host: "10.0.0.1", Port: 80, Weight: 100},
+				{Host: "10.0.0.2", Port: 80, Weight: 100},
+			},
+		},
+		Labels: types.Labels{"team": "a", "controller": "operator"},
+	}
+	localService := &types.Service{
+		ID:   "svc",
+		Name: "svc",
+		Upstream: types.Upstream{
+			Nodes: types.UpstreamNodes{
+				{Host: "httpbin.org", Port: 80, Weight: 1},
+			},
+		},
+		Labels: types.Labels{"team": "a"},
+	}
+
+	// Test case 1: the ignored fields aren't compared
+	local := *localService
+	differ, err := NewDiffer(&types.Configuration{
+		Services:     []*types.Service{&local},
+		IgnoreFields: []string{"service.*.upstream.nodes", "service.svc.labels.controller"},
+	}, &types.Configuration{
+		Services: []*types.Service{remoteService},
+	})
+	assert.Nil(t, err, "check the error")
+	events, err := differ.Diff()
+	assert.Nil(t, err, "check the error")
+	assert.Empty(t, events, "check the events")
+
+	// Test case 2: the ignored fields are kept as the remote in update payloads
+	local = *localService
+	local.Description = "new"
+	differ, _ = NewDiffer(&types.Configuration{
+		Services:     []*types.Service{&local},
+		IgnoreFields: []string{"service.svc.upstream.nodes"},
+	}, &types.Configuration{
+		Services: []*types.Service{remoteService},
+	})
+	events, _ = differ.Diff()
+	assert.Equal(t, 1, len(events), "check the number of events")
+	assert.Equal(t, data.UpdateOption, events[0].Option, "check the option")
+	updated := events[0].Value.(*types.Service)
+	assert.Equal(t, remoteService.Upstream.Nodes, updated.Upstream.Nodes, "check the nodes")
+	assert.Equal(t, types.Labels{"team": "a"}, updated.Labels, "check the labels")
+	assert.Equal(t, "new", updated.Description, "check the description")
+
+	// Test case 3: the rules of other resources don't apply
+	local = *localService
+	differ, _ = NewDiffer(&types.Configuration{
+		Services:     []*types.Service{&local},
+		IgnoreFields: []string{"service.other.upstream.nodes", "upstream.*.nodes"},
+	}, &types.Configuration{
+		Services: []*types.Service{remoteService},
+	})
+	events, _ = differ.Diff()
+	assert.Equal(t, 1, len(events), "check the number of events")
+
+	// Test case 4: invalid rules
+	_, err = NewDiffer(&types.Configuration{
+		IgnoreFields: []string{"service"},
+	}, &types.Configuration{})
+	assert.Equal(t, "invalid ignore rule 'service': it should be in the form of <resource type>.<id>.<field path>", err.Error(), "check the error")
+}
+
+func TestDiffIgnoreCredentialFields(t *testing.T) {
+	remoteConfig := &types.Configuration{
+		Consumers: []*types.Consumer{
+			{
+				Username: "jack",
+				Desc:     "remote",
+				Credentials: []*types.Credential{
+					{ID: "cred", Consumer: "jack", Desc: "rotated"},
+				},
+			},
+		},
+	}
+	localConfig := &types.Configuration{
+		Consumers: []*types.Consumer{
+			{
+				Username: "jack",
+				Desc:     "local",
+				Credentials: []*types.Credential{
+					{ID: "cred", Consumer: "jack", Desc: "initial"},
+				},
+			},
+		},
+		IgnoreFields: []string{"consumer.jack.desc", "credential.*.desc"},
+	}
+
+	// Test case 1: the credentials stay attached to the consumer
+	differ, _ := NewDiffer(localConfig, remoteConfig)
+	events, err := differ.Diff()
+	assert.Nil(t, err, "check the error")
+	assert.Empty(t, events, "check the events")
+	assert.Equal(t, "jack", localConfig.Consumers[0].Credentials[0].Consumer, "check the consumer of credential")
+}
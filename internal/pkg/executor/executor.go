@@ -0,0 +1,147 @@
+package executor
+
+import (
+	"container/heap"
+	"errors"
+
+	"github.com/api7/adc/pkg/api/apisix"
+	"github.com/api7/adc/pkg/data"
+)
+
+// Executor applies events to APISIX concurrently, an event is applied
+// only after all the events it depends on have been applied.
+type Executor struct {
+	parallelism int
+	apply       func(event *data.Event) error
+}
+
+// NewExecutor creates a new Executor object, at most parallelism events
+// are applied at the same time.
+func NewExecutor(cluster apisix.Cluster, parallelism int) (*Executor, error) {
+	if parallelism < 1 {
+		return nil, errors.New("parallelism must be at least 1")
+	}
+
+	return &Executor{
+		parallelism: parallelism,
+		apply: func(event *data.Event) error {
+			return event.Apply(cluster)
+		},
+	}, nil
+}
+
+type result struct {
+	index int
+	err   error
+}
+
+// Execute applies the events, callback is called (never concurrently) after
+// each event is applied. Once an event fails, no more events are started,
+// the running ones are waited for and the first error is returned.
+func (e *Executor) Execute(events []*data.Event, callback func(event *data.Event, err error)) error {
+	g := data.NewGraph(events)
+	sorted, err := g.Sort()
+	if err != nil {
+		return err
+	}
+
+	// ready events are started in the sorted order, so applying with
+	// parallelism 1 is the same as applying the sorted events one by one
+	position := make(map[*data.Event]int, len(sorted))
+	for i, event := range sorted {
+		position[event] = i
+	}
+	ready := &readyQueue{position: position, events: events}
+	pending := make([]int, len(events))
+	for i := range events {
+		pending[i] = len(g.Dependencies(i))
+		if pending[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	jobs := make(chan int)
+	results := make(chan result)
+	for w := 0; w < e.parallelism; w++ {
+		go func() {
+			for i := range jobs {
+				results <- result{index: i, err: e.apply(events[i])}
+			}
+		}()
+	}
+	defer close(jobs)
+
+	var firstErr error
+	running := 0
+	for {
+		for firstErr == nil && running < e.parallelism && ready.Len() > 0 {
+			jobs <- heap.Pop(ready).(int)
+			running++
+		}
+		if running == 0 {
+			break
+		}
+
+		res := <-results
+		running--
+		if callback != nil {
+			callback(events[res.index], res.err)
+		}
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			continue
+		}
+		for _, j := range g.Dependents(res.index) {
+			pending[j]--
+			if pending[j] == 0 {
+				heap.Push(ready, j)
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// Rollback reverts the applied events one by one in the reverse order, so the
+// dependents are reverted before their dependencies. It keeps going when an
+// event can't be reverted, callback is called with the original event after
+// each event is reverted, and the number of failures is returned.
+func (e *Executor) Rollback(applied []*data.Event, callback func(event *data.Event, err error)) int {
+	failed := 0
+	for i := len(applied) - 1; i >= 0; i-- {
+		err := e.apply(applied[i].Inverse())
+		if err != nil {
+			failed++
+		}
+		if callback != nil {
+			callback(applied[i], err)
+		}
+	}
+	return failed
+}
+
+// readyQueue is a priority queue of the indices of events which are ready to apply
+type readyQueue struct {
+	position map[*data.Event]int
+	events   []*data.Event
+	indices  []int
+}
+
+func (q *readyQueue) Len() int { return len(q.indices) }
+
+func (q *readyQueue) Less(i, j int) bool {
+	return q.position[q.events[q.indices[i]]] < q.position[q.events[q.indices[j]]]
+}
+
+func (q *readyQueue) Swap(i, j int) { q.indices[i], q.indices[j] = q.indices[j], q.indices[i] }
+
+func (q *readyQueue) Push(x interface{}) { q.indices = append(q.indices, x.(int)) }
+
+func (q *readyQueue) Pop() interface{} {
+	n := len(q.indices)
+	x := q.indices[n-1]
+	q.indices = q.indices[:n-1]
+	return x
+}
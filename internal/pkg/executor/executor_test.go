@@ -0,0 +1,137 @@
+package executor
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix"
+	"github.com/api7/adc/pkg/api/apisix/types"
+	"github.com/api7/adc/pkg/data"
+)
+
+func newTestEvents() []*data.Event {
+	var events []*data.Event
+	for _, id := range []string{"route1", "route2", "route3"} {
+		events = append(events, &data.Event{
+			ResourceType: data.RouteResourceType,
+			Option:       data.CreateOption,
+			Value:        &types.Route{ID: id, ServiceID: "svc"},
+		})
+	}
+	return append(events, &data.Event{
+		ResourceType: data.ServiceResourceType,
+		Option:       data.CreateOption,
+		Value:        &types.Service{ID: "svc", UpstreamId: "upstream"},
+	}, &data.Event{
+		ResourceType: data.UpstreamResourceType,
+		Option:       data.CreateOption,
+		Value:        &types.Upstream{ID: "upstream"},
+	})
+}
+
+func TestExecute(t *testing.T) {
+	// Test case 1: dependencies are applied first
+	var lock sync.Mutex
+	var applied []string
+	e := &Executor{
+		parallelism: 3,
+		apply: func(event *data.Event) error {
+			lock.Lock()
+			defer lock.Unlock()
+			applied = append(applied, apisix.GetResourceUniqueKey(event.Value))
+			return nil
+		},
+	}
+
+	var callbacks int
+	err := e.Execute(newTestEvents(), func(event *data.Event, err error) {
+		callbacks++
+		assert.Nil(t, err, "check the error of event")
+	})
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, 5, callbacks, "check the number of callbacks")
+	assert.Equal(t, []string{"upstream", "svc"}, applied[:2], "check the applied dependencies")
+	assert.ElementsMatch(t, []string{"route1", "route2", "route3"}, applied[2:], "check the applied routes")
+
+	// Test case 2: the dependents of a failed event are not applied
+	applied = nil
+	e.apply = func(event *data.Event) error {
+		lock.Lock()
+		defer lock.Unlock()
+		key := apisix.GetResourceUniqueKey(event.Value)
+		applied = append(applied, key)
+		if key == "svc" {
+			return errors.New("failed to apply service")
+		}
+		return nil
+	}
+	err = e.Execute(newTestEvents(), nil)
+	assert.EqualError(t, err, "failed to apply service")
+	assert.Equal(t, []string{"upstream", "svc"}, applied, "check the applied events")
+
+	// Test case 3: invalid parallelism
+	_, err = NewExecutor(nil, 0)
+	assert.EqualError(t, err, "parallelism must be at least 1")
+}
+
+func TestRollback(t *testing.T) {
+	var reverted []*data.Event
+	e := &Executor{
+		parallelism: 1,
+		apply: func(event *data.Event) error {
+			if event.ResourceType == data.UpstreamResourceType {
+				return errors.New("failed to apply upstream")
+			}
+			reverted = append(reverted, event)
+			return nil
+		},
+	}
+
+	upstream := &types.Upstream{ID: "upstream"}
+	svc := &types.Service{ID: "svc", UpstreamId: "upstream"}
+	oldSvc := &types.Service{ID: "svc"}
+	route := &types.Route{ID: "route", ServiceID: "svc"}
+	applied := []*data.Event{
+		{
+			ResourceType: data.UpstreamResourceType,
+			Option:       data.CreateOption,
+			Value:        upstream,
+		},
+		{
+			ResourceType: data.ServiceResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     oldSvc,
+			Value:        svc,
+		},
+		{
+			ResourceType: data.RouteResourceType,
+			Option:       data.DeleteOption,
+			OldValue:     route,
+		},
+	}
+
+	var failedEvents []*data.Event
+	failed := e.Rollback(applied, func(event *data.Event, err error) {
+		if err != nil {
+			failedEvents = append(failedEvents, event)
+		}
+	})
+	assert.Equal(t, 1, failed, "check the number of failures")
+	assert.Equal(t, []*data.Event{applied[0]}, failedEvents, "check the failed events")
+	assert.Equal(t, []*data.Event{
+		{
+			ResourceType: data.RouteResourceType,
+			Option:       data.CreateOption,
+			Value:        route,
+		},
+		{
+			ResourceType: data.ServiceResourceType,
+			Option:       data.UpdateOption,
+			OldValue:     svc,
+			Value:        oldSvc,
+		},
+	}, reverted, "check the reverted events")
+}
@@ -0,0 +1,335 @@
+package validator
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+	"github.com/api7/adc/pkg/common"
+	"github.com/api7/adc/pkg/data"
+)
+
+//go:embed schemas
+var embeddedSchemas embed.FS
+
+// schemasFS contains the JSON schemas of APISIX resources (<resource type>.json)
+// and plugins (plugins/<name>.json, and plugins/<name>.consumer.json for the
+// plugin configuration on consumers), generated from APISIX by `adc schemas generate`.
+var schemasFS, _ = fs.Sub(embeddedSchemas, "schemas")
+
+// SchemaResourceTypes are the resource types with embedded schemas.
+var SchemaResourceTypes = []string{"route", "service", "upstream", "consumer", "ssl", "plugin_config"}
+
+// SchemaError is an error of a resource against the embedded JSON schemas.
+type SchemaError struct {
+	ResourceType string
+	ID           string
+	// Path is the JSON path of the invalid field, e.g. plugins.limit-count.count,
+	// it's empty if the error is about the resource itself.
+	Path    string
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s (%s): %s", e.ResourceType, e.ID, e.Message)
+	}
+	return fmt.Sprintf("%s (%s): %s: %s", e.ResourceType, e.ID, e.Path, e.Message)
+}
+
+// ValidateOffline validates the local configuration with the embedded JSON schemas,
+// without connecting to APISIX. The plugins without embedded schemas, e.g. custom
+// plugins, fail the validation since they can't be checked.
+func (v *Validator) ValidateOffline() []error {
+	common.NormalizeConfiguration(v.localConfig)
+
+	s := &schemaValidator{
+		schemas: make(map[string]*gojsonschema.Schema),
+	}
+	for _, route := range v.localConfig.Routes {
+		s.validateResource("route", route.ID, route, route.Upstream, route.Plugins)
+	}
+	for _, service := range v.localConfig.Services {
+		s.validateResource("service", service.ID, service, &service.Upstream, service.Plugins)
+	}
+	for _, upstream := range v.localConfig.Upstreams {
+		s.validateResource("upstream", upstream.ID, upstream, nil, nil)
+	}
+	for _, consumer := range v.localConfig.Consumers {
+		s.validateResource("consumer", consumer.Username, consumer, nil, consumer.Plugins)
+		for _, credential := range consumer.Credentials {
+			s.validatePlugins("credential", credential.ResourceKey(), credential.Plugins, true)
+		}
+	}
+	for _, ssl := range v.localConfig.SSLs {
+		err := validateSSL(ssl)
+		if err != nil {
+			s.errs = append(s.errs, err)
+			continue
+		}
+		s.validateResource("ssl", ssl.ID, ssl, nil, nil)
+	}
+	for _, pluginConfig := range v.localConfig.PluginConfigs {
+		s.validateResource("plugin_config", pluginConfig.ID, pluginConfig, nil, pluginConfig.Plugins)
+	}
+	for _, globalRule := range v.localConfig.GlobalRules {
+		s.validatePlugins("global_rule", globalRule.ID, globalRule.Plugins, false)
+	}
+	for _, consumerGroup := range v.localConfig.ConsumerGroups {
+		s.validatePlugins("consumer_group", consumerGroup.ID, consumerGroup.Plugins, false)
+	}
+
+	return s.errs
+}
+
+type schemaValidator struct {
+	// schemas caches the compiled schemas by name, nil if the schema isn't embedded
+	schemas map[string]*gojsonschema.Schema
+	errs    []error
+}
+
+// validateResource validates the resource, the embedded upstream and the plugins of it.
+func (s *schemaValidator) validateResource(resourceType, id string, resource interface{}, upstream *types.Upstream, plugins types.Plugins) {
+	s.validate(resourceType, id, "", resourceType, resource)
+	if upstream != nil {
+		s.validate(resourceType, id, "upstream", "upstream", upstream)
+	}
+	s.validatePlugins(resourceType, id, plugins, resourceType == "consumer")
+}
+
+// validatePlugins validates the plugins, the consumer schemas of plugins are
+// preferred for the plugins on consumers and credentials.
+func (s *schemaValidator) validatePlugins(resourceType, id string, plugins types.Plugins, consumer bool) {
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schemaName := "plugins/" + name
+		if consumer && s.schema(schemaName+".consumer") != nil {
+			schemaName += ".consumer"
+		}
+		if s.schema(schemaName) == nil {
+			s.errs = append(s.errs, &SchemaError{
+				ResourceType: resourceType,
+				ID:           id,
+				Path:         data.JoinPath("plugins", name),
+				Message:      "no embedded schema of the plugin",
+			})
+			continue
+		}
+
+		// _meta is injected into the schemas of all plugins by APISIX
+		conf := make(types.Plugin, len(plugins[name]))
+		for key, value := range plugins[name] {
+			if key != "_meta" {
+				conf[key] = value
+			}
+		}
+		// APISIX fills in the default values before validating, e.g.
+		// the policy of limit-count decides which fields are required
+		conf = types.GetPluginDefaultValues(name, conf)
+		s.validate(resourceType, id, data.JoinPath("plugins", name), schemaName, conf)
+	}
+}
+
+// validate validates the value under the path of the resource with the schema.
+func (s *schemaValidator) validate(resourceType, id, path, schemaName string, value interface{}) {
+	schema := s.schema(schemaName)
+	if schema == nil {
+		return
+	}
+
+	document, err := toGeneric(value)
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("failed to validate resource '%s (%s)': %s", resourceType, id, err.Error()))
+		return
+	}
+	result, err := schema.Validate(gojsonschema.NewGoLoader(document))
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("failed to validate resource '%s (%s)': %s", resourceType, id, err.Error()))
+		return
+	}
+
+	var schemaErrs []*SchemaError
+	var combined []bool
+	for _, resultErr := range result.Errors() {
+		schemaErrs = append(schemaErrs, &SchemaError{
+			ResourceType: resourceType,
+			ID:           id,
+			Path:         contextPath(path, resultErr.Context(), document),
+			Message:      resultErr.Description(),
+		})
+		_, ok := combinedErrorTypes[resultErr.Type()]
+		combined = append(combined, ok)
+	}
+
+	for i, schemaErr := range schemaErrs {
+		if combined[i] && hasNestedError(schemaErrs, combined, schemaErr.Path) {
+			continue
+		}
+		s.errs = append(s.errs, schemaErr)
+	}
+}
+
+// combinedErrorTypes are the errors of combined schemas, e.g. "Must validate
+// at least one schema (anyOf)", they are left out if there are more specific errors.
+var combinedErrorTypes = map[string]struct{}{
+	"number_any_of":  {},
+	"number_one_of":  {},
+	"condition_then": {},
+	"condition_else": {},
+}
+
+// hasNestedError returns true if there is a specific error at or under the path.
+func hasNestedError(errs []*SchemaError, combined []bool, path string) bool {
+	for i, err := range errs {
+		if !combined[i] && (path == "" || err.Path == path || strings.HasPrefix(err.Path, path+".") || strings.HasPrefix(err.Path, path+"[")) {
+			return true
+		}
+	}
+	return false
+}
+
+// schema returns the compiled embedded schema, or nil if it doesn't exist.
+func (s *schemaValidator) schema(name string) *gojsonschema.Schema {
+	if schema, ok := s.schemas[name]; ok {
+		return schema
+	}
+
+	var schema *gojsonschema.Schema
+	content, err := fs.ReadFile(schemasFS, name+".json")
+	if err == nil {
+		schema, err = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(content))
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		// the embedded schemas are tested, it should never happen
+		panic(fmt.Sprintf("invalid embedded schema %s: %s", name, err.Error()))
+	}
+	s.schemas[name] = schema
+	return schema
+}
+
+func toGeneric(value interface{}) (interface{}, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	err = json.Unmarshal(content, &generic)
+	return generic, err
+}
+
+// contextDelimiter separates the fields of the error context, it can't be in JSON keys
+const contextDelimiter = "\x00"
+
+// contextPath converts the error context, e.g. (root)/nodes/0/port, into the
+// JSON path under the base path, e.g. upstream.nodes[0].port. The document is
+// used to tell the array indices from the object keys.
+func contextPath(base string, context *gojsonschema.JsonContext, document interface{}) string {
+	if context == nil {
+		return base
+	}
+
+	path := base
+	fields := strings.Split(context.String(contextDelimiter), contextDelimiter)
+	// the first field is always (root)
+	for _, field := range fields[1:] {
+		switch value := document.(type) {
+		case []interface{}:
+			index, err := strconv.Atoi(field)
+			if err == nil && index < len(value) {
+				path = data.IndexPath(path, index)
+				document = value[index]
+				continue
+			}
+		case map[string]interface{}:
+			document = value[field]
+		}
+		path = data.JoinPath(path, field)
+	}
+	return path
+}
+
+var (
+	// arrayKeywords are the keywords whose values must be non-empty arrays,
+	// APISIX encodes the empty Lua tables as {}, e.g. "required": {}
+	arrayKeywords = map[string]struct{}{"required": {}, "enum": {}, "anyOf": {}, "oneOf": {}, "allOf": {}}
+	// schemaMapKeywords are the keywords whose values are objects of schemas,
+	// APISIX encodes them as [] if they are empty
+	schemaMapKeywords = map[string]struct{}{"properties": {}, "patternProperties": {}, "definitions": {}, "dependencies": {}}
+	// valueKeywords are the keywords whose values aren't schemas
+	valueKeywords = map[string]struct{}{"default": {}, "const": {}, "examples": {}}
+)
+
+// NormalizeSchema converts the JSON schema returned by the Admin API of APISIX
+// to a valid JSON schema, and formats it with sorted keys for embedding.
+func NormalizeSchema(content string) ([]byte, error) {
+	var schema interface{}
+	err := json.Unmarshal([]byte(content), &schema)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := json.MarshalIndent(normalizeSchema(schema), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(normalized, '\n'), nil
+}
+
+func normalizeSchema(schema interface{}) interface{} {
+	switch value := schema.(type) {
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalizeSchema(item)
+		}
+	case map[string]interface{}:
+		for key, item := range value {
+			if _, ok := valueKeywords[key]; ok {
+				continue
+			}
+			if _, ok := arrayKeywords[key]; ok && isEmpty(item) {
+				delete(value, key)
+				continue
+			}
+			if _, ok := schemaMapKeywords[key]; ok {
+				if isEmpty(item) {
+					value[key] = map[string]interface{}{}
+					continue
+				}
+				if schemas, ok := item.(map[string]interface{}); ok {
+					// the keys are the names of properties, not keywords
+					for name, s := range schemas {
+						schemas[name] = normalizeSchema(s)
+					}
+					continue
+				}
+			}
+			value[key] = normalizeSchema(item)
+		}
+	}
+	return schema
+}
+
+// isEmpty returns true if the value is an empty object or array
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package validator
+
+import (
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func TestEmbeddedSchemas(t *testing.T) {
+	s := &schemaValidator{schemas: make(map[string]*gojsonschema.Schema)}
+	err := fs.WalkDir(schemasFS, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return err
+		}
+		name := strings.TrimSuffix(path, ".json")
+		assert.NotNil(t, s.schema(name), "check the schema "+name)
+		return nil
+	})
+	assert.Nil(t, err, "check the error")
+}
+
+func TestValidateOffline(t *testing.T) {
+	// the schemas in testdata don't change with the embedded schemas
+	embedded := schemasFS
+	schemasFS = os.DirFS("testdata/schemas")
+	defer func() {
+		schemasFS = embedded
+	}()
+
+	cert := strings.Repeat("c", 128)
+	config := &types.Configuration{
+		Routes: []*types.Route{
+			{
+				ID:  "valid",
+				Uri: "/get",
+				Upstream: &types.Upstream{
+					Nodes: types.UpstreamNodes{{Host: "httpbin.org", Port: 80, Weight: 1}},
+				},
+				Plugins: types.Plugins{
+					"limit-count": {"count": 10, "time_window": 60, "_meta": map[string]interface{}{"disable": false}},
+					"my-plugin":   {"foo": "bar"},
+				},
+			},
+			{
+				ID:     "invalid",
+				Uri:    "/get",
+				Labels: types.Labels{"app.kubernetes.io/name": "has space"},
+				Upstream: &types.Upstream{
+					Nodes: types.UpstreamNodes{{Host: "httpbin.org", Port: 70000, Weight: 1}},
+				},
+				Plugins: types.Plugins{
+					"limit-count": {"count": 0, "time_window": 60},
+				},
+			},
+		},
+		Consumers: []*types.Consumer{
+			{
+				Username: "jack",
+				Plugins: types.Plugins{
+					"key-auth": {"header": "apikey"},
+				},
+				Credentials: []*types.Credential{
+					{ID: "cred", Plugins: types.Plugins{"basic-auth": {"username": "jack", "password": "secret"}}},
+				},
+			},
+		},
+		SSLs: []*types.SSL{
+			{ID: "ssl", SNIs: []string{"foo.com"}, Cert: cert, Key: "$secret://vault/ssl/key"},
+			{ID: "short", SNIs: []string{"foo.com"}, Cert: "cert", Key: cert},
+		},
+		PluginConfigs: []*types.PluginConfig{
+			{ID: "pc", Plugins: types.Plugins{"ip-restriction": {"whitelist": []interface{}{"10.0.0.0/8"}}}},
+		},
+	}
+
+	v, _ := NewValidator(config, nil)
+	errs := v.ValidateOffline()
+
+	// Test case 1: the errors carry the resource type, ID and JSON path,
+	// the plugins without schemas fail the validation
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		"route (valid): plugins.my-plugin: no embedded schema of the plugin",
+		`route (invalid): labels["app.kubernetes.io/name"]: Does not match pattern '^\S+$'`,
+		"route (invalid): upstream.nodes[0].port: Must be less than or equal to 65535",
+		"route (invalid): plugins.limit-count.count: Must be greater than 0",
+		"consumer (jack): plugins.key-auth: key is required",
+		"ssl (short): cert: String length must be greater than or equal to 128",
+	}, messages, "check the errors")
+	assert.Equal(t, &SchemaError{
+		ResourceType: "route",
+		ID:           "invalid",
+		Path:         "upstream.nodes[0].port",
+		Message:      "Must be less than or equal to 65535",
+	}, errs[2], "check the error fields")
+}
+
+func TestNormalizeSchema(t *testing.T) {
+	// Test case 1: the empty Lua tables encoded by APISIX
+	content, err := NormalizeSchema(`{
+		"type": "object",
+		"properties": {
+			"required": {"type": "array", "default": {}},
+			"headers": {"type": "object", "properties": []}
+		},
+		"required": {},
+		"anyOf": [{"required": ["headers"]}, {"properties": [], "required": {}}]
+	}`)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, `{
+  "anyOf": [
+    {
+      "required": [
+        "headers"
+      ]
+    },
+    {
+      "properties": {}
+    }
+  ],
+  "properties": {
+    "headers": {
+      "properties": {},
+      "type": "object"
+    },
+    "required": {
+      "default": {},
+      "type": "array"
+    }
+  },
+  "type": "object"
+}
+`, string(content), "check the normalized schema")
+
+	_, err = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(content))
+	assert.Nil(t, err, "check the schema is valid")
+
+	// Test case 2: invalid JSON
+	_, err = NormalizeSchema(`{"type":`)
+	assert.NotNil(t, err, "check the error")
+}
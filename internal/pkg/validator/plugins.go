@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+// CheckPlugins checks that every plugin referenced by the local configuration
+// is enabled on the APISIX cluster, so that sync won't fail halfway.
+func (v *Validator) CheckPlugins() []error {
+	enabled, err := v.cluster.Plugin().List(context.Background())
+	if err != nil {
+		return []error{fmt.Errorf("failed to list enabled plugins: %s", err.Error())}
+	}
+
+	// the stream plugins are only listed when there are stream routes
+	var enabledStream []string
+	if len(v.localConfig.StreamRoutes) > 0 {
+		enabledStream, err = v.cluster.Plugin().ListStream(context.Background())
+		if err != nil {
+			return []error{fmt.Errorf("failed to list enabled stream plugins: %s", err.Error())}
+		}
+	}
+
+	return checkPlugins(v.localConfig, enabled, enabledStream)
+}
+
+// checkPlugins returns an error for each plugin which isn't enabled,
+// naming the resources which use it. The plugins of stream routes are
+// checked against the enabled stream plugins.
+func checkPlugins(config *types.Configuration, enabled, enabledStream []string) []error {
+	enabledSet := make(map[string]struct{}, len(enabled))
+	for _, name := range enabled {
+		enabledSet[name] = struct{}{}
+	}
+	enabledStreamSet := make(map[string]struct{}, len(enabledStream))
+	for _, name := range enabledStream {
+		enabledStreamSet[name] = struct{}{}
+	}
+
+	// plugin name => resources which use the plugin
+	missing := make(map[string][]string)
+	collectIn := func(enabledSet map[string]struct{}, resourceType, id string, plugins types.Plugins) {
+		for name := range plugins {
+			if _, ok := enabledSet[name]; !ok {
+				missing[name] = append(missing[name], fmt.Sprintf("%s (%s)", resourceType, id))
+			}
+		}
+	}
+	collect := func(resourceType, id string, plugins types.Plugins) {
+		collectIn(enabledSet, resourceType, id, plugins)
+	}
+
+	for _, route := range config.Routes {
+		collect("route", route.ID, route.Plugins)
+	}
+	for _, streamRoute := range config.StreamRoutes {
+		collectIn(enabledStreamSet, "stream_route", streamRoute.ID, streamRoute.Plugins)
+	}
+	for _, service := range config.Services {
+		collect("service", service.ID, service.Plugins)
+	}
+	for _, consumer := range config.Consumers {
+		collect("consumer", consumer.Username, consumer.Plugins)
+		for _, credential := range consumer.Credentials {
+			collect("credential", credential.ResourceKey(), credential.Plugins)
+		}
+	}
+	for _, consumerGroup := range config.ConsumerGroups {
+		collect("consumer_group", consumerGroup.ID, consumerGroup.Plugins)
+	}
+	for _, globalRule := range config.GlobalRules {
+		collect("global_rule", globalRule.ID, globalRule.Plugins)
+	}
+	for _, pluginConfig := range config.PluginConfigs {
+		collect("plugin_config", pluginConfig.ID, pluginConfig.Plugins)
+	}
+	for _, pluginMetadata := range config.PluginMetadatas {
+		// the ID of plugin metadata is the plugin name
+		collect("plugin_metadata", pluginMetadata.ID, types.Plugins{pluginMetadata.ID: nil})
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, fmt.Errorf("plugin '%s' is not enabled on APISIX, used by: %s", name, strings.Join(missing[name], ", ")))
+	}
+	return errs
+}
@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func TestCheckPlugins(t *testing.T) {
+	config := &types.Configuration{
+		Routes: []*types.Route{
+			{
+				ID: "route",
+				Plugins: types.Plugins{
+					"key-auth":  {},
+					"my-plugin": {},
+				},
+			},
+		},
+		StreamRoutes: []*types.StreamRoute{
+			{
+				ID: "sr",
+				Plugins: types.Plugins{
+					"mqtt-proxy": {},
+				},
+			},
+		},
+		Services: []*types.Service{
+			{
+				ID: "svc",
+				Plugins: types.Plugins{
+					"my-plugin": {},
+				},
+			},
+		},
+		Consumers: []*types.Consumer{
+			{
+				Username: "jack",
+				Credentials: []*types.Credential{
+					{
+						ID:       "cred",
+						Consumer: "jack",
+						Plugins: types.Plugins{
+							"jwt-auth": {},
+						},
+					},
+				},
+			},
+		},
+		PluginMetadatas: []*types.PluginMetadata{
+			{
+				ID: "http-logger",
+			},
+		},
+	}
+
+	// Test case 1: all plugins are enabled
+	errs := checkPlugins(config, []string{"key-auth", "my-plugin", "jwt-auth", "http-logger"}, []string{"mqtt-proxy"})
+	assert.Equal(t, 0, len(errs), "check the number of errors")
+
+	// Test case 2: some plugins are not enabled
+	errs = checkPlugins(config, []string{"key-auth"}, nil)
+	assert.Equal(t, 4, len(errs), "check the number of errors")
+	assert.EqualError(t, errs[0], "plugin 'http-logger' is not enabled on APISIX, used by: plugin_metadata (http-logger)")
+	assert.EqualError(t, errs[1], "plugin 'jwt-auth' is not enabled on APISIX, used by: credential (jack/credentials/cred)")
+	assert.EqualError(t, errs[2], "plugin 'mqtt-proxy' is not enabled on APISIX, used by: stream_route (sr)")
+	assert.EqualError(t, errs[3], "plugin 'my-plugin' is not enabled on APISIX, used by: route (route), service (svc)")
+
+	// Test case 3: the plugins of stream routes are checked against the stream plugins
+	errs = checkPlugins(config, []string{"key-auth", "my-plugin", "jwt-auth", "http-logger", "mqtt-proxy"}, nil)
+	assert.Equal(t, 1, len(errs), "check the number of errors")
+	assert.EqualError(t, errs[0], "plugin 'mqtt-proxy' is not enabled on APISIX, used by: stream_route (sr)")
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/api7/adc/pkg/api/apisix"
 	"github.com/api7/adc/pkg/api/apisix/types"
@@ -36,6 +37,26 @@ func (v ErrorsWrapper) Error() string {
 	return errStr
 }
 
+// validateSSL checks the SSL fields which depend on each other,
+// the schema of each single field is validated by APISIX.
+func validateSSL(ssl *types.SSL) error {
+	var err error
+	switch {
+	case len(ssl.Certs) != len(ssl.Keys):
+		err = fmt.Errorf("the number of certs (%d) and keys (%d) doesn't match", len(ssl.Certs), len(ssl.Keys))
+	case ssl.Type != types.SSLTypeClient && len(ssl.SNIs) == 0:
+		err = errors.New("snis is required for server SSL")
+	case ssl.Type == types.SSLTypeClient && ssl.Client != nil:
+		err = errors.New("client verification is only available for server SSL")
+	case ssl.Client != nil && ssl.Client.CA == "":
+		err = errors.New("client.ca is required for client verification")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to validate resource 'ssls (%s)': %s", ssl.ID, err.Error())
+	}
+	return nil
+}
+
 func (v *Validator) Validate() []error {
 	allErr := []error{}
 
@@ -49,6 +70,14 @@ func (v *Validator) Validate() []error {
 		}
 	}
 
+	for _, upstream := range v.localConfig.Upstreams {
+		upstream := upstream
+		err := v.cluster.Upstream().Validate(context.Background(), upstream)
+		if err != nil {
+			allErr = append(allErr, err)
+		}
+	}
+
 	for _, route := range v.localConfig.Routes {
 		route := route
 		err := v.cluster.Route().Validate(context.Background(), route)
@@ -57,6 +86,14 @@ func (v *Validator) Validate() []error {
 		}
 	}
 
+	for _, streamRoute := range v.localConfig.StreamRoutes {
+		streamRoute := streamRoute
+		err := v.cluster.StreamRoute().Validate(context.Background(), streamRoute)
+		if err != nil {
+			allErr = append(allErr, err)
+		}
+	}
+
 	for _, consumer := range v.localConfig.Consumers {
 		consumer := consumer
 		err := v.cluster.Consumer().Validate(context.Background(), consumer)
@@ -67,7 +104,12 @@ func (v *Validator) Validate() []error {
 
 	for _, ssl := range v.localConfig.SSLs {
 		ssl := ssl
-		err := v.cluster.SSL().Validate(context.Background(), ssl)
+		err := validateSSL(ssl)
+		if err != nil {
+			allErr = append(allErr, err)
+			continue
+		}
+		err = v.cluster.SSL().Validate(context.Background(), ssl)
 		if err != nil {
 			allErr = append(allErr, err)
 		}
@@ -97,6 +139,14 @@ func (v *Validator) Validate() []error {
 		}
 	}
 
+	for _, proto := range v.localConfig.Protos {
+		proto := proto
+		err := v.cluster.Proto().Validate(context.Background(), proto)
+		if err != nil {
+			allErr = append(allErr, err)
+		}
+	}
+
 	// TODO: enable this when APISIX supports
 	//for _, pluginMetadata := range v.localConfig.PluginMetadatas {
 	//	pluginMetadata := pluginMetadata
@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func TestValidateSSL(t *testing.T) {
+	// Test case 1: server SSL with multiple certificate pairs and client verification
+	ssl := &types.SSL{
+		ID:    "ssl",
+		SNIs:  []string{"foo.com"},
+		Cert:  "cert",
+		Key:   "key",
+		Certs: []string{"ecc-cert"},
+		Keys:  []string{"ecc-key"},
+		Client: &types.SSLClient{
+			CA:    "ca",
+			Depth: 2,
+		},
+		SSLProtocols: []string{"TLSv1.2", "TLSv1.3"},
+	}
+	assert.Nil(t, validateSSL(ssl), "check the server SSL")
+
+	// Test case 2: client SSL doesn't require snis
+	ssl = &types.SSL{
+		ID:   "client",
+		Type: types.SSLTypeClient,
+		Cert: "cert",
+		Key:  "key",
+	}
+	assert.Nil(t, validateSSL(ssl), "check the client SSL")
+
+	// Test case 3: certs and keys don't match
+	ssl = &types.SSL{
+		ID:    "ssl",
+		SNIs:  []string{"foo.com"},
+		Certs: []string{"cert1", "cert2"},
+		Keys:  []string{"key1"},
+	}
+	assert.EqualError(t, validateSSL(ssl), "failed to validate resource 'ssls (ssl)': the number of certs (2) and keys (1) doesn't match")
+
+	// Test case 4: server SSL without snis
+	ssl = &types.SSL{
+		ID:   "ssl",
+		Cert: "cert",
+		Key:  "key",
+	}
+	assert.EqualError(t, validateSSL(ssl), "failed to validate resource 'ssls (ssl)': snis is required for server SSL")
+
+	// Test case 5: client verification without ca
+	ssl = &types.SSL{
+		ID:     "ssl",
+		SNIs:   []string{"foo.com"},
+		Client: &types.SSLClient{Depth: 1},
+	}
+	assert.EqualError(t, validateSSL(ssl), "failed to validate resource 'ssls (ssl)': client.ca is required for client verification")
+}
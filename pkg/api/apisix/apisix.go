@@ -8,13 +8,22 @@ import (
 
 type Cluster interface {
 	Route() Route
+	StreamRoute() StreamRoute
 	Service() Service
+	Upstream() Upstream
 	Consumer() Consumer
 	SSL() SSL
 	GlobalRule() GlobalRule
 	PluginConfig() PluginConfig
 	ConsumerGroup() ConsumerGroup
 	PluginMetadata() PluginMetadata
+	Proto() Proto
+	Secret() Secret
+	Plugin() Plugin
+	// Version returns the version of APISIX, e.g. 3.6.0
+	Version(ctx context.Context) (string, error)
+	// Schema returns the JSON schema of the resource type, e.g. route
+	Schema(ctx context.Context, resourceType string) (string, error)
 }
 
 type ResourceClient[T any] interface {
@@ -30,12 +39,26 @@ type Route interface {
 	ResourceClient[types.Route]
 }
 
+type StreamRoute interface {
+	ResourceClient[types.StreamRoute]
+}
+
 type Service interface {
 	ResourceClient[types.Service]
 }
 
+type Upstream interface {
+	ResourceClient[types.Upstream]
+}
+
 type Consumer interface {
 	ResourceClient[types.Consumer]
+	Credential() Credential
+}
+
+type Credential interface {
+	ResourceClient[types.Credential]
+	ListByConsumer(ctx context.Context, username string) ([]*types.Credential, error)
 }
 
 type SSL interface {
@@ -57,3 +80,24 @@ type ConsumerGroup interface {
 type PluginMetadata interface {
 	ResourceClient[types.PluginMetadata]
 }
+
+type Proto interface {
+	ResourceClient[types.Proto]
+}
+
+type Secret interface {
+	ResourceClient[types.Secret]
+}
+
+// Plugin lists the plugins enabled on APISIX and their schemas.
+type Plugin interface {
+	// List returns the names of enabled plugins.
+	List(ctx context.Context) ([]string, error)
+	// ListStream returns the names of enabled stream plugins.
+	ListStream(ctx context.Context) ([]string, error)
+	// Schema returns the JSON schema of the plugin.
+	Schema(ctx context.Context, name string) (string, error)
+	// ConsumerSchema returns the JSON schema of the plugin configuration
+	// on consumers, e.g. the key of key-auth.
+	ConsumerSchema(ctx context.Context, name string) (string, error)
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -135,7 +136,6 @@ func readBody(r io.ReadCloser) (string, error) {
 	return string(data), nil
 }
 
-//nolint:unused
 // getSchema returns the schema of APISIX object.
 func (c *Client) getSchema(ctx context.Context, url string) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -167,19 +167,57 @@ func (c *Client) getSchema(ctx context.Context, url string) (string, error) {
 	return body, nil
 }
 
-//nolint:unused
-// getList returns a list of string.
+// getVersion returns the APISIX version in the Server header of the response,
+// which is in the form of "APISIX/3.6.0".
+func (c *Client) getVersion(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", handleErrorResponse(resp)
+	}
+
+	server := resp.Header.Get("Server")
+	version, ok := strings.CutPrefix(server, "APISIX/")
+	if !ok || version == "" {
+		return "", fmt.Errorf("unknown server: %s", server)
+	}
+	return version, nil
+}
+
+// getList returns a sorted list of string.
+// The response can be either a list of string or an object keyed by names.
 func (c *Client) getList(ctx context.Context, url string) ([]string, error) {
-	var listResp map[string]interface{}
+	var listResp json.RawMessage
 	err := makeGetRequest(c, ctx, url, &listResp)
 	if err != nil {
 		return nil, err
 	}
-	res := make([]string, 0, len(listResp))
 
-	for name := range listResp {
-		res = append(res, name)
+	var res []string
+	if len(listResp) > 0 && listResp[0] == '[' {
+		if err := json.Unmarshal(listResp, &res); err != nil {
+			return nil, err
+		}
+	} else {
+		var objResp map[string]interface{}
+		if err := json.Unmarshal(listResp, &objResp); err != nil {
+			return nil, err
+		}
+		res = make([]string, 0, len(objResp))
+		for name := range objResp {
+			res = append(res, name)
+		}
 	}
+	sort.Strings(res)
+
 	return res, nil
 }
 
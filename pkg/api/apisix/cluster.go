@@ -20,13 +20,18 @@ type cluster struct {
 	cli *Client
 
 	route          Route
+	streamRoute    StreamRoute
 	service        Service
+	upstream       Upstream
 	consumer       Consumer
 	ssl            SSL
 	globalRule     GlobalRule
 	pluginConfig   PluginConfig
 	consumerGroup  ConsumerGroup
 	pluginMetadata PluginMetadata
+	proto          Proto
+	secret         Secret
+	plugin         Plugin
 }
 
 func NewCluster(ctx context.Context, conf config.ClientConfig) (Cluster, error) {
@@ -78,13 +83,18 @@ func NewCluster(ctx context.Context, conf config.ClientConfig) (Cluster, error)
 
 	c.cli = cli
 	c.route = newRoute(cli)
+	c.streamRoute = newStreamRoute(cli)
 	c.service = newService(cli)
+	c.upstream = newUpstream(cli)
 	c.consumer = newConsumer(cli)
 	c.ssl = newSSL(cli)
 	c.globalRule = newGlobalRule(cli)
 	c.pluginConfig = newPluginConfig(cli)
 	c.consumerGroup = newConsumerGroup(cli)
 	c.pluginMetadata = newPluginMetadata(cli)
+	c.proto = newProto(cli)
+	c.secret = newSecret(cli)
+	c.plugin = newPlugin(cli)
 
 	return c, nil
 }
@@ -94,11 +104,21 @@ func (c *cluster) Route() Route {
 	return c.route
 }
 
+// StreamRoute implements Cluster.StreamRoute method.
+func (c *cluster) StreamRoute() StreamRoute {
+	return c.streamRoute
+}
+
 // Service implements Cluster.Service method.
 func (c *cluster) Service() Service {
 	return c.service
 }
 
+// Upstream implements Cluster.Upstream method.
+func (c *cluster) Upstream() Upstream {
+	return c.upstream
+}
+
 // Consumer implements Cluster.Consumer method.
 func (c *cluster) Consumer() Consumer {
 	return c.consumer
@@ -128,3 +148,28 @@ func (c *cluster) ConsumerGroup() ConsumerGroup {
 func (c *cluster) PluginMetadata() PluginMetadata {
 	return c.pluginMetadata
 }
+
+// Proto implements Cluster.Proto method.
+func (c *cluster) Proto() Proto {
+	return c.proto
+}
+
+// Secret implements Cluster.Secret method.
+func (c *cluster) Secret() Secret {
+	return c.secret
+}
+
+// Plugin implements Cluster.Plugin method.
+func (c *cluster) Plugin() Plugin {
+	return c.plugin
+}
+
+// Version implements Cluster.Version method.
+func (c *cluster) Version(ctx context.Context) (string, error) {
+	return c.cli.getVersion(ctx, adminBaseURL(c.cli)+"plugins/list")
+}
+
+// Schema implements Cluster.Schema method.
+func (c *cluster) Schema(ctx context.Context, resourceType string) (string, error) {
+	return c.cli.getSchema(ctx, adminBaseURL(c.cli)+"schema/"+resourceType)
+}
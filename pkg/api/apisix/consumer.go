@@ -2,25 +2,144 @@ package apisix
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/api7/adc/pkg/api/apisix/types"
 )
 
 type consumerClient struct {
 	*resourceClient[types.Consumer]
+
+	credential *credentialClient
 }
 
 func newConsumer(c *Client) Consumer {
 	cli := newResourceClient[types.Consumer](c, "consumers")
 	return &consumerClient{
 		resourceClient: cli,
+		credential:     newCredential(c),
+	}
+}
+
+// Credential returns the client of the consumer credentials.
+func (u *consumerClient) Credential() Credential {
+	return u.credential
+}
+
+func (u *consumerClient) Get(ctx context.Context, username string) (*types.Consumer, error) {
+	consumer, err := u.resourceClient.Get(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer.Credentials, err = u.credential.ListByConsumer(ctx, username)
+	if err != nil {
+		return nil, err
 	}
+	return consumer, nil
 }
 
+func (u *consumerClient) List(ctx context.Context) ([]*types.Consumer, error) {
+	consumers, err := u.resourceClient.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, consumer := range consumers {
+		consumer.Credentials, err = u.credential.ListByConsumer(ctx, consumer.Username)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return consumers, nil
+}
+
+// Create creates the consumer only, the credentials are created separately.
 func (u *consumerClient) Create(ctx context.Context, obj *types.Consumer) (*types.Consumer, error) {
-	return u.resourceClient.Create(ctx, obj.Username, obj)
+	return u.resourceClient.Create(ctx, obj.Username, obj.WithoutCredentials())
 }
 
+// Update updates the consumer only, the credentials are updated separately.
 func (u *consumerClient) Update(ctx context.Context, obj *types.Consumer) (*types.Consumer, error) {
-	return u.resourceClient.Update(ctx, obj.Username, obj)
+	return u.resourceClient.Update(ctx, obj.Username, obj.WithoutCredentials())
+}
+
+func (u *consumerClient) Validate(ctx context.Context, obj *types.Consumer) error {
+	return u.resourceClient.Validate(ctx, obj.WithoutCredentials())
+}
+
+// credentialClient manages the credentials under consumers/{username}/credentials/{id},
+// the resource key of a credential is "{username}/credentials/{id}".
+type credentialClient struct {
+	*resourceClient[types.Credential]
+}
+
+func newCredential(c *Client) *credentialClient {
+	cli := newResourceClient[types.Credential](c, "consumers")
+	// credentials are nested under consumers, but have their own schema
+	cli.validateURL = cli.baseURL + "schema/validate/credentials"
+	return &credentialClient{
+		resourceClient: cli,
+	}
+}
+
+// ListByConsumer lists the credentials of the consumer. It returns an empty list
+// when APISIX doesn't support credentials.
+func (u *credentialClient) ListByConsumer(ctx context.Context, username string) ([]*types.Credential, error) {
+	items, err := u.client.listResource(ctx, u.resourceURL+"/"+username+"/credentials")
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var credentials []*types.Credential
+	for _, item := range items {
+		credential, err := unmarshalItem[types.Credential](&item)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, nil
+}
+
+// List lists the credentials of all consumers.
+func (u *credentialClient) List(ctx context.Context) ([]*types.Credential, error) {
+	consumers, err := u.client.listResource(ctx, u.resourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var credentials []*types.Credential
+	for _, item := range consumers {
+		consumer, err := unmarshalItem[types.Consumer](&item)
+		if err != nil {
+			return nil, err
+		}
+		items, err := u.ListByConsumer(ctx, consumer.Username)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, items...)
+	}
+	return credentials, nil
+}
+
+func (u *credentialClient) Create(ctx context.Context, obj *types.Credential) (*types.Credential, error) {
+	return u.resourceClient.Create(ctx, obj.ResourceKey(), obj)
+}
+
+func (u *credentialClient) Update(ctx context.Context, obj *types.Credential) (*types.Credential, error) {
+	return u.resourceClient.Update(ctx, obj.ResourceKey(), obj)
+}
+
+func (u *credentialClient) Validate(ctx context.Context, obj *types.Credential) error {
+	err := u.client.validate(ctx, u.validateURL, obj)
+	if err != nil {
+		return fmt.Errorf("failed to validate resource 'credential (%s)': %s", obj.ResourceKey(), err.Error())
+	}
+	return nil
 }
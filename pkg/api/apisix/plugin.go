@@ -0,0 +1,40 @@
+package apisix
+
+import (
+	"context"
+)
+
+type pluginClient struct {
+	listURL   string
+	schemaURL string
+	client    *Client
+}
+
+func newPlugin(c *Client) Plugin {
+	baseURL := adminBaseURL(c)
+	return &pluginClient{
+		listURL:   baseURL + "plugins/list",
+		schemaURL: baseURL + "schema/plugins/",
+		client:    c,
+	}
+}
+
+// List implements Plugin.List method.
+func (p *pluginClient) List(ctx context.Context) ([]string, error) {
+	return p.client.getList(ctx, p.listURL)
+}
+
+// ListStream implements Plugin.ListStream method.
+func (p *pluginClient) ListStream(ctx context.Context) ([]string, error) {
+	return p.client.getList(ctx, p.listURL+"?subsystem=stream")
+}
+
+// Schema implements Plugin.Schema method.
+func (p *pluginClient) Schema(ctx context.Context, name string) (string, error) {
+	return p.client.getSchema(ctx, p.schemaURL+name)
+}
+
+// ConsumerSchema implements Plugin.ConsumerSchema method.
+func (p *pluginClient) ConsumerSchema(ctx context.Context, name string) (string, error) {
+	return p.client.getSchema(ctx, p.schemaURL+name+"?schema_type=consumer")
+}
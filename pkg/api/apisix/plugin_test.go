@@ -0,0 +1,70 @@
+package apisix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginClient(t *testing.T) {
+	// path => response body
+	responses := map[string]string{
+		"/apisix/admin/plugins/list":                                 `["limit-count", "cors", "key-auth"]`,
+		"/apisix/admin/schema/plugins/key-auth":                      `{"type":"object","properties":{"header":{"type":"string"}}}`,
+		"/apisix/admin/plugins/list?subsystem=stream":                `["mqtt-proxy", "ip-restriction"]`,
+		"/apisix/admin/schema/plugins/key-auth?schema_type=consumer": `{"type":"object","properties":{"key":{"type":"string"}},"required":["key"]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "admin-key", r.Header.Get("X-API-KEY"), "check the admin key")
+		path := r.URL.Path
+		if r.URL.RawQuery != "" {
+			path += "?" + r.URL.RawQuery
+		}
+		body, ok := responses[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	plugin := newPlugin(newClient(server.URL, "admin-key"))
+
+	// Test case 1: the plugin list in an array
+	plugins, err := plugin.List(context.Background())
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, []string{"cors", "key-auth", "limit-count"}, plugins, "check the sorted plugins")
+
+	// Test case 2: the plugin list in an object, returned by some APISIX versions
+	responses["/apisix/admin/plugins/list"] = `{"limit-count": {}, "cors": {}, "key-auth": {}}`
+	plugins, err = plugin.List(context.Background())
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, []string{"cors", "key-auth", "limit-count"}, plugins, "check the sorted plugins")
+
+	// Test case 3: invalid responses
+	responses["/apisix/admin/plugins/list"] = `"cors"`
+	_, err = plugin.List(context.Background())
+	assert.NotNil(t, err, "check the error")
+
+	// Test case 4: the schema of plugin
+	schema, err := plugin.Schema(context.Background(), "key-auth")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, responses["/apisix/admin/schema/plugins/key-auth"], schema, "check the schema")
+
+	_, err = plugin.Schema(context.Background(), "unknown")
+	assert.Equal(t, ErrNotFound, err, "check the error of unknown plugin")
+
+	// Test case 5: the stream plugins
+	streamPlugins, err := plugin.ListStream(context.Background())
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, []string{"ip-restriction", "mqtt-proxy"}, streamPlugins, "check the stream plugins")
+
+	// Test case 6: the consumer schema of plugin
+	schema, err = plugin.ConsumerSchema(context.Background(), "key-auth")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, responses["/apisix/admin/schema/plugins/key-auth?schema_type=consumer"], schema, "check the consumer schema")
+}
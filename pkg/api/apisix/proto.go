@@ -0,0 +1,26 @@
+package apisix
+
+import (
+	"context"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+type protoClient struct {
+	*resourceClient[types.Proto]
+}
+
+func newProto(c *Client) Proto {
+	cli := newResourceClient[types.Proto](c, "protos")
+	return &protoClient{
+		resourceClient: cli,
+	}
+}
+
+func (u *protoClient) Create(ctx context.Context, obj *types.Proto) (*types.Proto, error) {
+	return u.resourceClient.Create(ctx, obj.ID, obj)
+}
+
+func (u *protoClient) Update(ctx context.Context, obj *types.Proto) (*types.Proto, error) {
+	return u.resourceClient.Update(ctx, obj.ID, obj)
+}
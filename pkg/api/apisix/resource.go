@@ -82,5 +82,17 @@ func unmarshalItem[T any](i *item) (*T, error) {
 		any(&obj).(*types.PluginMetadata).ID = list[len(list)-1]
 	}
 
+	// patch Credential since the consumer username is only carried by the key
+	if credential, ok := any(&obj).(*types.Credential); ok && len(list) >= 3 {
+		credential.Consumer = list[len(list)-3]
+		credential.ID = list[len(list)-1]
+	}
+
+	// patch Secret since the manager type and ID are only carried by the key
+	if secret, ok := any(&obj).(*types.Secret); ok && len(list) >= 2 {
+		secret.Manager = list[len(list)-2]
+		secret.ID = list[len(list)-1]
+	}
+
 	return &obj, nil
 }
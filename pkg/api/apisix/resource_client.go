@@ -16,7 +16,8 @@ type resourceClient[T any] struct {
 	client       *Client
 }
 
-func newResourceClient[T any](c *Client, resourceName string) *resourceClient[T] {
+// adminBaseURL returns the base URL of Admin API, which ends with "apisix/admin/".
+func adminBaseURL(c *Client) string {
 	baseURL := c.baseURL
 	if !strings.HasSuffix(baseURL, "/") {
 		baseURL += "/"
@@ -24,6 +25,11 @@ func newResourceClient[T any](c *Client, resourceName string) *resourceClient[T]
 	if !strings.HasSuffix(baseURL, "apisix/admin/") {
 		baseURL += "apisix/admin/"
 	}
+	return baseURL
+}
+
+func newResourceClient[T any](c *Client, resourceName string) *resourceClient[T] {
+	baseURL := adminBaseURL(c)
 
 	return &resourceClient[T]{
 		baseURL:      baseURL,
@@ -109,7 +115,17 @@ func (u *resourceClient[T]) Update(ctx context.Context, id string, obj *T) (*T,
 	return svc, err
 }
 
+// resourceKeyer is implemented by the resources which can't be
+// identified by a single field, such as secrets.
+type resourceKeyer interface {
+	ResourceKey() string
+}
+
 func GetResourceUniqueKey(resource interface{}) string {
+	if keyer, ok := resource.(resourceKeyer); ok {
+		return keyer.ResourceKey()
+	}
+
 	value := reflect.ValueOf(resource)
 	value = reflect.Indirect(value)
 	nameOrID := value.FieldByName("ID")
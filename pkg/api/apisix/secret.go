@@ -0,0 +1,48 @@
+package apisix
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+// secretClient manages the secrets, they are addressed by two segments:
+// secrets/{manager}/{id}, so the resource key is passed as "{manager}/{id}".
+type secretClient struct {
+	*resourceClient[types.Secret]
+}
+
+func newSecret(c *Client) Secret {
+	cli := newResourceClient[types.Secret](c, "secrets")
+	return &secretClient{
+		resourceClient: cli,
+	}
+}
+
+// put writes the secret config only, the manager and ID are carried by the URL.
+func (u *secretClient) put(ctx context.Context, obj *types.Secret) (*types.Secret, error) {
+	config := obj.Config
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+	body, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	url := u.resourceURL + "/" + obj.ResourceKey()
+	resp, err := u.client.updateResource(ctx, url, body)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalItem[types.Secret](resp)
+}
+
+func (u *secretClient) Create(ctx context.Context, obj *types.Secret) (*types.Secret, error) {
+	return u.put(ctx, obj)
+}
+
+func (u *secretClient) Update(ctx context.Context, obj *types.Secret) (*types.Secret, error) {
+	return u.put(ctx, obj)
+}
@@ -0,0 +1,36 @@
+package apisix
+
+import (
+	"context"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+type streamRouteClient struct {
+	*resourceClient[types.StreamRoute]
+}
+
+func newStreamRoute(c *Client) StreamRoute {
+	cli := newResourceClient[types.StreamRoute](c, "stream_routes")
+	return &streamRouteClient{
+		resourceClient: cli,
+	}
+}
+
+// List returns an empty list when the stream mode of APISIX is disabled,
+// so that HTTP-only instances can still be dumped and synced.
+func (u *streamRouteClient) List(ctx context.Context) ([]*types.StreamRoute, error) {
+	items, err := u.resourceClient.List(ctx)
+	if err != nil && isFunctionDisabled(err.Error()) {
+		return nil, nil
+	}
+	return items, err
+}
+
+func (u *streamRouteClient) Create(ctx context.Context, obj *types.StreamRoute) (*types.StreamRoute, error) {
+	return u.resourceClient.Create(ctx, obj.ID, obj)
+}
+
+func (u *streamRouteClient) Update(ctx context.Context, obj *types.StreamRoute) (*types.StreamRoute, error) {
+	return u.resourceClient.Update(ctx, obj.ID, obj)
+}
@@ -0,0 +1,249 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// ignoredSchemaKeys are the validation keywords which don't affect the default values
+var ignoredSchemaKeys = []string{
+	"description",
+	"$comment",
+	"enum",
+	"required",
+	"minItems",
+	"maxItems",
+	"uniqueItems",
+	"minLength",
+	"maxLength",
+	"minimum",
+	"maximum",
+	"pattern",
+	"encrypt_fields",
+	"minProperties",
+	"title",
+}
+
+// ReadPluginSchemas reads the JSON schemas of plugins from the directory,
+// each schema is in the file named <plugin name>.json.
+func ReadPluginSchemas(dir string) (map[string]map[string]interface{}, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]map[string]interface{}, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var schema map[string]interface{}
+		err = json.Unmarshal(content, &schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the schema %s: %s", file, err.Error())
+		}
+		schemas[strings.TrimSuffix(filepath.Base(file), ".json")] = schema
+	}
+	return schemas, nil
+}
+
+// GenerateDefaultValues extracts the default values of plugins from their
+// JSON schemas, the result is in the format of data/default_values.json.
+// The plugins without default values are left out.
+func GenerateDefaultValues(schemas map[string]map[string]interface{}) map[string]interface{} {
+	defaults := make(map[string]interface{})
+	for name, schema := range schemas {
+		// manual patch: the authorization of openfunction has no defaults
+		// but a nested schema that is hard to merge
+		if name == "openfunction" {
+			if properties, ok := schema["properties"].(map[string]interface{}); ok {
+				delete(properties, "authorization")
+			}
+		}
+
+		value := ExtractDefaultValues(schema)
+		if value == nil {
+			continue
+		}
+
+		// manual patch: the sampler of opentelemetry is an object with a default value
+		if name == "opentelemetry" {
+			if object, ok := value.(map[string]interface{}); ok {
+				if sampler, ok := object["sampler"].(map[string]interface{}); ok {
+					object["sampler"] = sampler["default"]
+				}
+			}
+		}
+		defaults[name] = value
+	}
+	return defaults
+}
+
+// ExtractDefaultValues walks the JSON schema and returns the default values,
+// or nil if there is none. The properties of objects are flattened into the
+// object, while the keywords needed to apply the defaults, e.g. "default",
+// "items", "if"/"then"/"else" and "anyOf", are kept, see SetDefaultValue.
+// Note that it modifies the schema.
+func ExtractDefaultValues(schema interface{}) interface{} {
+	object, ok := schema.(map[string]interface{})
+	if !ok {
+		return schema
+	}
+
+	value := extractDefaultValues(object)
+	if value == nil {
+		return nil
+	}
+	if object, ok := value.(map[string]interface{}); ok {
+		if len(object) == 0 {
+			return nil
+		}
+		return filterType(object)
+	}
+	return value
+}
+
+func extractDefaultValues(schema map[string]interface{}) interface{} {
+	for _, key := range ignoredSchemaKeys {
+		delete(schema, key)
+	}
+
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		subSchemas, ok := schema[key].([]interface{})
+		if !ok {
+			continue
+		}
+		empty := true
+		for i, subSchema := range subSchemas {
+			subSchemas[i] = ExtractDefaultValues(subSchema)
+			empty = empty && subSchemas[i] == nil
+		}
+		if empty {
+			delete(schema, key)
+		}
+	}
+
+	if _, ok := schema["if"]; ok {
+		for _, key := range []string{"then", "else"} {
+			value := ExtractDefaultValues(schema[key])
+			if value == nil {
+				delete(schema, key)
+			} else {
+				schema[key] = value
+			}
+		}
+	}
+
+	typ, hasType := schema["type"]
+	properties, hasProperties := schema["properties"].(map[string]interface{})
+	switch {
+	case typ == "object" || (!hasType && hasProperties):
+		patternProperties, hasPatternProperties := schema["patternProperties"].(map[string]interface{})
+		if !hasProperties && !hasPatternProperties {
+			// no properties, return it as is
+			if _, ok := schema["default"]; !ok {
+				return nil
+			}
+			return schema
+		}
+
+		if hasProperties {
+			extractProperties(properties)
+			if len(properties) == 0 {
+				delete(schema, "properties")
+				hasProperties = false
+			}
+
+			def, hasDefault := schema["default"]
+			if hasProperties && hasDefault && reflect.DeepEqual(properties, def) {
+				delete(schema, "default")
+				hasDefault = false
+			}
+			if hasProperties && !hasDefault {
+				for key, value := range properties {
+					schema[key] = value
+				}
+				delete(schema, "properties")
+			}
+		}
+
+		if hasPatternProperties {
+			extractProperties(patternProperties)
+			if len(patternProperties) == 0 {
+				delete(schema, "patternProperties")
+			}
+		}
+
+		delete(schema, "type")
+	case typ == "array":
+		items, ok := schema["items"]
+		if !ok {
+			return schema
+		}
+		value := ExtractDefaultValues(items)
+		if value == nil {
+			delete(schema, "items")
+		} else {
+			schema["items"] = value
+		}
+	case typ == "string" || typ == "integer" || typ == "number" || typ == "boolean":
+		if _, ok := schema["default"]; !ok {
+			// no default value, ignore
+			return nil
+		}
+	}
+
+	return schema
+}
+
+// extractProperties replaces the schema of properties with their default values,
+// and removes the properties without default values.
+func extractProperties(properties map[string]interface{}) {
+	for key, schema := range properties {
+		value := ExtractDefaultValues(schema)
+		if value == nil {
+			delete(properties, key)
+		} else {
+			properties[key] = value
+		}
+	}
+}
+
+// filterType simplifies the schema which only has a type, a default value or properties.
+func filterType(schema map[string]interface{}) interface{} {
+	typ, hasType := schema["type"]
+	def, hasDefault := schema["default"]
+	properties, hasProperties := schema["properties"]
+	emptyProperties := false
+	if object, ok := properties.(map[string]interface{}); ok {
+		emptyProperties = len(object) == 0
+	}
+
+	switch len(schema) {
+	case 1:
+		switch {
+		case hasType:
+			return nil
+		case hasProperties && emptyProperties:
+			return nil
+		case hasDefault:
+			return def
+		case hasProperties:
+			return properties
+		}
+	case 2:
+		switch {
+		case typ == "object" && hasProperties && emptyProperties:
+			return nil
+		case hasType && hasDefault:
+			return def
+		}
+	}
+	return schema
+}
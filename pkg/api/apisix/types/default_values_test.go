@@ -0,0 +1,74 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDefaultValues(t *testing.T) {
+	schemas, err := ReadPluginSchemas("testdata/schemas")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, 5, len(schemas), "check the number of schemas")
+
+	defaults := GenerateDefaultValues(schemas)
+
+	// Test case 1: the plugins without default values are left out
+	_, ok := defaults["echo"]
+	assert.False(t, ok, "check the plugin without default values")
+
+	// Test case 2: the same default values as the embedded ones,
+	// including nested objects, "default" with "properties" and if-then-else
+	for _, name := range []string{"api-breaker", "request-id", "limit-count"} {
+		expected, _ := json.Marshal(PluginDefaultValues[name])
+		generated, _ := json.Marshal(defaults[name])
+		assert.JSONEq(t, string(expected), string(generated), "check the default values of "+name)
+	}
+
+	// Test case 3: array item schemas
+	generated, _ := json.Marshal(defaults["kafka-logger"])
+	assert.JSONEq(t, `{
+  "brokers": {
+    "items": {
+      "sasl_config": {
+        "mechanism": "PLAIN"
+      }
+    },
+    "type": "array"
+  },
+  "producer_type": "async",
+  "required_acks": 1,
+  "timeout": 3
+}`, string(generated), "check the default values of kafka-logger")
+
+	// Test case 4: the generated default values can be applied
+	PluginDefaultValues["kafka-logger-generated"] = defaults["kafka-logger"].(map[string]interface{})
+	defer delete(PluginDefaultValues, "kafka-logger-generated")
+	plugin := GetPluginDefaultValues("kafka-logger-generated", Plugin{
+		"brokers": []interface{}{
+			map[string]interface{}{"host": "127.0.0.1", "port": 9092},
+		},
+	})
+	OutputEqual(t, plugin, `{
+  "brokers": [
+    {
+      "host": "127.0.0.1",
+      "port": 9092,
+      "sasl_config": {
+        "mechanism": "PLAIN"
+      }
+    }
+  ],
+  "producer_type": "async",
+  "required_acks": 1,
+  "timeout": 3
+}`)
+}
+
+func TestReadPluginSchemas(t *testing.T) {
+	// Test case 1: the directory doesn't exist
+	schemas, err := ReadPluginSchemas("testdata/not-exist")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, 0, len(schemas), "check the number of schemas")
+}
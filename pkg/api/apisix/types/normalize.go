@@ -0,0 +1,79 @@
+package types
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+var (
+	// ResourceDefaultValues are the default values filled in by APISIX on
+	// the server side, taken from the core schemas in apisix/schema_def.lua.
+	//go:embed data/resource_default_values.json
+	ResourceDefaultValues []byte
+
+	resourceDefaultValues map[string]map[string]interface{}
+)
+
+func init() {
+	err := json.Unmarshal(ResourceDefaultValues, &resourceDefaultValues)
+	if err != nil {
+		panic("failed to parse resource defaults values")
+	}
+
+	// the inline upstreams of routes and services share the defaults of upstream
+	for _, resourceType := range []string{"route", "service"} {
+		resourceDefaultValues[resourceType]["upstream"] = resourceDefaultValues["upstream"]
+	}
+}
+
+// Normalize returns a copy of the resource with the server-side default
+// values of the resource type (route, service, upstream or ssl) filled in,
+// so that a resource without them equals the one read from APISIX.
+// The resources of other types are returned as they are.
+func Normalize[T any](resourceType string, resource *T) (*T, error) {
+	defaults, ok := resourceDefaultValues[resourceType]
+	if !ok || resource == nil {
+		return resource, nil
+	}
+
+	content, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	err = json.Unmarshal(content, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err = json.Marshal(fillDefaultValues(object, defaults))
+	if err != nil {
+		return nil, err
+	}
+	var normalized T
+	err = json.Unmarshal(content, &normalized)
+	if err != nil {
+		return nil, err
+	}
+	return &normalized, nil
+}
+
+// fillDefaultValues sets the missing fields of the object to the default values.
+// Unlike SetDefaultValue, the nested objects are only filled if they exist,
+// e.g. an upstream without health checks doesn't get the default checks.
+func fillDefaultValues(object, defaults map[string]interface{}) map[string]interface{} {
+	for key, defaultValue := range defaults {
+		value, ok := object[key]
+		defaultObj, isObj := defaultValue.(map[string]interface{})
+		if isObj {
+			if obj, ok := value.(map[string]interface{}); ok {
+				object[key] = fillDefaultValues(obj, defaultObj)
+			}
+			continue
+		}
+		if !ok || value == nil {
+			object[key] = defaultValue
+		}
+	}
+	return object
+}
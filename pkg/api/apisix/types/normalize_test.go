@@ -0,0 +1,80 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	// Test case 1: the defaults of upstream are filled in
+	upstream := &Upstream{
+		ID:    "upstream",
+		Nodes: UpstreamNodes{{Host: "httpbin.org", Port: 80, Weight: 1}},
+	}
+	normalized, err := Normalize("upstream", upstream)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, &Upstream{
+		ID:       "upstream",
+		Type:     "roundrobin",
+		HashOn:   "vars",
+		Scheme:   "http",
+		PassHost: "pass",
+		Nodes:    UpstreamNodes{{Host: "httpbin.org", Port: 80, Weight: 1}},
+	}, normalized, "check the normalized upstream")
+	assert.Equal(t, "", upstream.Scheme, "check the original upstream isn't changed")
+
+	// Test case 2: the existing values are kept, the existing nested objects are filled in
+	upstream = &Upstream{
+		ID:     "upstream",
+		Type:   "chash",
+		HashOn: "header",
+		Key:    "X-User",
+		Checks: &UpstreamHealthCheck{
+			Active: &UpstreamActiveHealthCheck{HTTPPath: "/status"},
+		},
+	}
+	normalized, _ = Normalize("upstream", upstream)
+	assert.Equal(t, "chash", normalized.Type, "check the type")
+	assert.Equal(t, "header", normalized.HashOn, "check the hash_on")
+	assert.Equal(t, "/status", normalized.Checks.Active.HTTPPath, "check the http path")
+	assert.Equal(t, 10, normalized.Checks.Active.Concurrency, "check the concurrency")
+	assert.Equal(t, []int{200, 302}, normalized.Checks.Active.Healthy.HTTPStatuses, "check the healthy statuses")
+	assert.Nil(t, normalized.Checks.Passive, "check the passive check isn't added")
+	assert.Nil(t, normalized.KeepalivePool, "check the keepalive pool isn't added")
+
+	// Test case 3: the keepalive pool
+	upstream = &Upstream{
+		ID:            "upstream",
+		KeepalivePool: &UpstreamKeepalivePool{Size: 100},
+	}
+	normalized, _ = Normalize("upstream", upstream)
+	assert.Equal(t, &UpstreamKeepalivePool{Size: 100, IdleTimeout: 60, Requests: 1000}, normalized.KeepalivePool, "check the keepalive pool")
+
+	// Test case 4: the embedded upstream of route and the status
+	route := &Route{
+		ID:       "route",
+		Uri:      "/get",
+		Upstream: &Upstream{Nodes: UpstreamNodes{}, KeepalivePool: &UpstreamKeepalivePool{}},
+	}
+	normalizedRoute, _ := Normalize("route", route)
+	enabled := 1
+	assert.Equal(t, &enabled, normalizedRoute.Status, "check the status")
+	assert.Equal(t, "http", normalizedRoute.Upstream.Scheme, "check the upstream scheme")
+	assert.Equal(t, 320, normalizedRoute.Upstream.KeepalivePool.Size, "check the upstream keepalive pool")
+	normalizedRoute, _ = Normalize("route", &Route{ID: "route"})
+	assert.Nil(t, normalizedRoute.Upstream, "check the upstream isn't added")
+
+	// Test case 5: the embedded upstream of service
+	normalizedService, _ := Normalize("service", &Service{ID: "service"})
+	assert.Equal(t, "roundrobin", normalizedService.Upstream.Type, "check the upstream type")
+
+	// Test case 6: SSL
+	normalizedSSL, _ := Normalize("ssl", &SSL{ID: "ssl"})
+	assert.Equal(t, &SSL{ID: "ssl", Type: "server", Status: &enabled}, normalizedSSL, "check the normalized ssl")
+
+	// Test case 7: other resource types are kept as they are
+	consumer := &Consumer{Username: "jack"}
+	normalizedConsumer, _ := Normalize("consumer", consumer)
+	assert.Same(t, consumer, normalizedConsumer, "check the consumer")
+}
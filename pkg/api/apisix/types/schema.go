@@ -1,24 +1,125 @@
 package types
 
 import (
-	_ "embed"
+	"embed"
 	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// DefaultAPISIXVersion is the APISIX version of the plugin default values used
+// if the version of the target APISIX is unknown.
+const DefaultAPISIXVersion = "3.6"
+
 var (
-	//go:embed data/default_values.json
+	//go:embed data/default_values
+	embeddedDefaultValues embed.FS
+
+	// defaultValuesFS contains the plugin default values of each APISIX version,
+	// in the files named <major>.<minor>.json.
+	defaultValuesFS, _ = fs.Sub(embeddedDefaultValues, "data/default_values")
+
+	// DefaultValues is the content of plugin default values in use
 	DefaultValues []byte
 
 	PluginDefaultValues map[string]Plugin
 )
 
 func init() {
-	err := json.Unmarshal(DefaultValues, &PluginDefaultValues)
+	_, err := UseAPISIXVersion(DefaultAPISIXVersion)
 	if err != nil {
 		panic("failed to parse plugin defaults values")
 	}
 }
 
+// APISIXVersions returns the APISIX versions with the plugin default values, in ascending order.
+func APISIXVersions() []string {
+	entries, _ := fs.ReadDir(defaultValuesFS, ".")
+
+	var versions []string
+	for _, entry := range entries {
+		versions = append(versions, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		a, _ := parseVersion(versions[i])
+		b, _ := parseVersion(versions[j])
+		return a < b
+	})
+	return versions
+}
+
+// UseAPISIXVersion switches to the plugin default values of the APISIX version,
+// e.g. 3.2 or 3.2.1, and returns the version of the default values in use.
+// If there are no default values of the version, the nearest version is used,
+// the older one if two versions are equally near.
+func UseAPISIXVersion(version string) (string, error) {
+	selected, err := nearestVersion(APISIXVersions(), version)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := fs.ReadFile(defaultValuesFS, selected+".json")
+	if err != nil {
+		return "", err
+	}
+	var defaults map[string]Plugin
+	err = json.Unmarshal(content, &defaults)
+	if err != nil {
+		return "", err
+	}
+
+	DefaultValues = content
+	PluginDefaultValues = defaults
+	return selected, nil
+}
+
+// nearestVersion returns the version in versions (in ascending order) nearest
+// to the version, the older one if two versions are equally near.
+func nearestVersion(versions []string, version string) (string, error) {
+	target, err := parseVersion(version)
+	if err != nil {
+		return "", err
+	}
+
+	selected := ""
+	distance := -1
+	for _, v := range versions {
+		n, _ := parseVersion(v)
+		d := n - target
+		if d < 0 {
+			d = -d
+		}
+		if distance < 0 || d < distance {
+			selected, distance = v, d
+		}
+	}
+	if selected == "" {
+		return "", fmt.Errorf("no plugin default values")
+	}
+	return selected, nil
+}
+
+// parseVersion parses the major and minor version into a comparable number,
+// the patch version and the suffix (e.g. 3.2.1-debian) are ignored.
+func parseVersion(version string) (int, error) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return 0, fmt.Errorf("invalid APISIX version: %s", version)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid APISIX version: %s", version)
+	}
+	minor, err := strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid APISIX version: %s", version)
+	}
+	return major*1000 + minor, nil
+}
+
 // Return true if the value is primitive types or array of primitive types,
 // and very simple objects (without reserved keys)
 func isBasicTypes(value interface{}) bool {
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"os"
 	"strings"
 	"testing"
 
@@ -410,3 +411,77 @@ func TestLimitCount(t *testing.T) {
 //
 //	OutputEqual(t, plugin, ``)
 //}
+
+func TestUseAPISIXVersion(t *testing.T) {
+	assert.Equal(t, []string{"3.6"}, APISIXVersions(), "check the embedded versions")
+
+	// the tables of 3.2 and 3.6 in testdata, loki-logger is added in 3.6
+	embedded := defaultValuesFS
+	defaultValuesFS = os.DirFS("testdata/default_values")
+	defer func() {
+		defaultValuesFS = embedded
+		_, _ = UseAPISIXVersion(DefaultAPISIXVersion)
+	}()
+
+	assert.Equal(t, []string{"3.2", "3.6"}, APISIXVersions(), "check the versions")
+
+	// Test case 1: the exact versions load different tables
+	selected, err := UseAPISIXVersion("3.2.1")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, "3.2", selected, "check the selected version")
+	_, ok := PluginDefaultValues["loki-logger"]
+	assert.False(t, ok, "check the plugin doesn't exist in 3.2")
+	_, ok = PluginDefaultValues["limit-count"]
+	assert.True(t, ok, "check the plugin exists in 3.2")
+
+	selected, err = UseAPISIXVersion("3.6.0")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, "3.6", selected, "check the selected version")
+	_, ok = PluginDefaultValues["loki-logger"]
+	assert.True(t, ok, "check the plugin exists in 3.6")
+
+	// Test case 2: the nearest version
+	selected, err = UseAPISIXVersion("3.3")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, "3.2", selected, "check the selected version")
+	_, ok = PluginDefaultValues["loki-logger"]
+	assert.False(t, ok, "check the default values of 3.2 are used")
+
+	selected, err = UseAPISIXVersion("3.9.1")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, "3.6", selected, "check the selected version")
+
+	// Test case 3: invalid versions keep the default values in use
+	_, err = UseAPISIXVersion("latest")
+	assert.Equal(t, "invalid APISIX version: latest", err.Error(), "check the error")
+	_, ok = PluginDefaultValues["loki-logger"]
+	assert.True(t, ok, "check the default values are kept")
+}
+
+func TestNearestVersion(t *testing.T) {
+	versions := []string{"3.2", "3.6"}
+
+	// Test case 1: the exact version
+	selected, err := nearestVersion(versions, "3.2.1")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, "3.2", selected, "check the selected version")
+
+	// Test case 2: the nearest version
+	for _, c := range []struct {
+		version  string
+		expected string
+	}{
+		{version: "2.15.3", expected: "3.2"},
+		{version: "3.4", expected: "3.2"},
+		{version: "3.9.1-debian", expected: "3.6"},
+		{version: "3.5.0", expected: "3.6"},
+	} {
+		selected, err = nearestVersion(versions, c.version)
+		assert.Nil(t, err, "check the error")
+		assert.Equal(t, c.expected, selected, "check the selected version of "+c.version)
+	}
+
+	// Test case 3: no versions
+	_, err = nearestVersion(nil, "3.6")
+	assert.Equal(t, "no plugin default values", err.Error(), "check the error")
+}
@@ -14,13 +14,21 @@ type Configuration struct {
 	Name            string            `yaml:"name" json:"name"`
 	Version         string            `yaml:"version" json:"version"`
 	Services        []*Service        `yaml:"services,omitempty" json:"services,omitempty"`
+	Upstreams       []*Upstream       `yaml:"upstreams,omitempty" json:"upstreams,omitempty"`
 	Routes          []*Route          `yaml:"routes,omitempty" json:"routes,omitempty"`
+	StreamRoutes    []*StreamRoute    `yaml:"stream_routes,omitempty" json:"stream_routes,omitempty"`
 	Consumers       []*Consumer       `yaml:"consumers,omitempty" json:"consumers,omitempty"`
 	SSLs            []*SSL            `yaml:"ssls,omitempty" json:"ssls,omitempty"`
 	GlobalRules     []*GlobalRule     `yaml:"global_rules,omitempty" json:"global_rules,omitempty"`
 	PluginConfigs   []*PluginConfig   `yaml:"plugin_configs,omitempty" json:"plugin_configs,omitempty"`
 	ConsumerGroups  []*ConsumerGroup  `yaml:"consumer_groups,omitempty" json:"consumer_groups,omitempty"`
 	PluginMetadatas []*PluginMetadata `yaml:"plugin_metadatas,omitempty" json:"plugin_metadatas,omitempty"`
+	Protos          []*Proto          `yaml:"protos,omitempty" json:"protos,omitempty"`
+	Secrets         []*Secret         `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+
+	// IgnoreFields are the paths of fields managed out of band, which are
+	// ignored by diff and sync, e.g. service.*.upstream.nodes
+	IgnoreFields []string `yaml:"ignore_fields,omitempty" json:"ignore_fields,omitempty"`
 }
 
 // Labels is the APISIX resource labels
@@ -46,12 +54,38 @@ type Route struct {
 	Uris            []string         `json:"uris,omitempty" yaml:"uris,omitempty"`
 	Methods         []string         `json:"methods,omitempty" yaml:"methods,omitempty"`
 	EnableWebsocket bool             `json:"enable_websocket,omitempty" yaml:"enable_websocket,omitempty"`
+	RemoteAddr      string           `json:"remote_addr,omitempty" yaml:"remote_addr,omitempty"`
 	RemoteAddrs     []string         `json:"remote_addrs,omitempty" yaml:"remote_addrs,omitempty"`
+	Upstream        *Upstream        `json:"upstream,omitempty" yaml:"upstream,omitempty"`
 	UpstreamId      string           `json:"upstream_id,omitempty" yaml:"upstream_id,omitempty"`
 	ServiceID       string           `json:"service_id,omitempty" yaml:"service_id,omitempty"`
 	Plugins         Plugins          `json:"plugins,omitempty" yaml:"plugins,omitempty"`
 	PluginConfigId  string           `json:"plugin_config_id,omitempty" yaml:"plugin_config_id,omitempty"`
 	FilterFunc      string           `json:"filter_func,omitempty" yaml:"filter_func,omitempty"`
+	Script          string           `json:"script,omitempty" yaml:"script,omitempty"`
+	ScriptID        string           `json:"script_id,omitempty" yaml:"script_id,omitempty"`
+	ServiceProtocol string           `json:"service_protocol,omitempty" yaml:"service_protocol,omitempty"`
+
+	// Status enables (1) or disables (0) the route, it's a pointer
+	// so that a disabled route isn't omitted.
+	Status *int `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// StreamRoute apisix stream route object, used for L4 TCP/UDP proxying
+type StreamRoute struct {
+	ID string `json:"id" yaml:"id"`
+
+	Description string `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Labels      Labels `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	ServerAddr string    `json:"server_addr,omitempty" yaml:"server_addr,omitempty"`
+	ServerPort int       `json:"server_port,omitempty" yaml:"server_port,omitempty"`
+	RemoteAddr string    `json:"remote_addr,omitempty" yaml:"remote_addr,omitempty"`
+	SNI        string    `json:"sni,omitempty" yaml:"sni,omitempty"`
+	Upstream   *Upstream `json:"upstream,omitempty" yaml:"upstream,omitempty"`
+	UpstreamId string    `json:"upstream_id,omitempty" yaml:"upstream_id,omitempty"`
+	ServiceID  string    `json:"service_id,omitempty" yaml:"service_id,omitempty"`
+	Plugins    Plugins   `json:"plugins,omitempty" yaml:"plugins,omitempty"`
 }
 
 // Service is the abstraction of a backend service on API gateway.
@@ -74,35 +108,44 @@ type Service struct {
 	EnableWebsocket bool `json:"enable_websocket,omitempty" yaml:"enable_websocket,omitempty"`
 }
 
-// Upstream is the definition of the upstream on Service.
+// Upstream is the definition of the upstream on Service,
+// or a standalone upstream referenced by upstream_id.
 type Upstream struct {
 	// ID is the upstream name. It should be unique among all upstreams
-	// in the same service.
+	// in the same service, or among all standalone upstreams.
 	ID string `json:"id" yaml:"id"`
 
-	Name     string               `json:"name" yaml:"name"`
-	Type     string               `json:"type,omitempty" yaml:"type,omitempty"`
-	HashOn   string               `json:"hash_on,omitempty" yaml:"hash_on,omitempty"`
-	Key      string               `json:"key,omitempty" yaml:"key,omitempty"`
-	Checks   *UpstreamHealthCheck `json:"checks,omitempty" yaml:"checks,omitempty"`
-	Nodes    UpstreamNodes        `json:"nodes" yaml:"nodes"`
-	Scheme   string               `json:"scheme,omitempty" yaml:"scheme,omitempty"`
-	Retries  *int                 `json:"retries,omitempty" yaml:"retries,omitempty"`
-	Timeout  *UpstreamTimeout     `json:"timeout,omitempty" yaml:"timeout,omitempty"`
-	TLS      *ClientTLS           `json:"tls,omitempty" yaml:"tls,omitempty"`
-	PassHost string               `json:"passhost,omitempty" yaml:"passhostomitempty"`
+	Name   string `json:"name" yaml:"name"`
+	Desc   string `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Labels Labels `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	Type          string                 `json:"type,omitempty" yaml:"type,omitempty"`
+	HashOn        string                 `json:"hash_on,omitempty" yaml:"hash_on,omitempty"`
+	Key           string                 `json:"key,omitempty" yaml:"key,omitempty"`
+	Checks        *UpstreamHealthCheck   `json:"checks,omitempty" yaml:"checks,omitempty"`
+	Nodes         UpstreamNodes          `json:"nodes" yaml:"nodes"`
+	Scheme        string                 `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	Retries       *int                   `json:"retries,omitempty" yaml:"retries,omitempty"`
+	RetryTimeout  *int                   `json:"retry_timeout,omitempty" yaml:"retry_timeout,omitempty"`
+	Timeout       *UpstreamTimeout       `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	TLS           *ClientTLS             `json:"tls,omitempty" yaml:"tls,omitempty"`
+	KeepalivePool *UpstreamKeepalivePool `json:"keepalive_pool,omitempty" yaml:"keepalive_pool,omitempty"`
+	PassHost      string                 `json:"pass_host,omitempty" yaml:"pass_host,omitempty"`
+	UpstreamHost  string                 `json:"upstream_host,omitempty" yaml:"upstream_host,omitempty"`
 
 	// for Service Discovery
-	ServiceName   string            `json:"service_name,omitempty" yaml:"service_name,omitempty"`
-	DiscoveryType string            `json:"discovery_type,omitempty" yaml:"discovery_type,omitempty"`
-	DiscoveryArgs map[string]string `json:"discovery_args,omitempty" yaml:"discovery_args,omitempty"`
+	ServiceName   string                 `json:"service_name,omitempty" yaml:"service_name,omitempty"`
+	DiscoveryType string                 `json:"discovery_type,omitempty" yaml:"discovery_type,omitempty"`
+	DiscoveryArgs map[string]interface{} `json:"discovery_args,omitempty" yaml:"discovery_args,omitempty"`
 }
 
 // UpstreamNode is the node in upstream
 type UpstreamNode struct {
-	Host   string `json:"host,omitempty" yaml:"host,omitempty"`
-	Port   int    `json:"port,omitempty" yaml:"port,omitempty"`
-	Weight int    `json:"weight,omitempty" yaml:"weight,omitempty"`
+	Host     string                 `json:"host,omitempty" yaml:"host,omitempty"`
+	Port     int                    `json:"port,omitempty" yaml:"port,omitempty"`
+	Weight   int                    `json:"weight,omitempty" yaml:"weight,omitempty"`
+	Priority int                    `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
 // UpstreamNodes is the upstream node list.
@@ -201,6 +244,18 @@ type UpstreamPassiveHealthCheckUnhealthy struct {
 type ClientTLS struct {
 	Cert string `json:"client_cert,omitempty" yaml:"client_cert,omitempty"`
 	Key  string `json:"client_key,omitempty" yaml:"client_key,omitempty"`
+	// CertID references an SSL object of client type instead of inline cert and key
+	CertID string `json:"client_cert_id,omitempty" yaml:"client_cert_id,omitempty"`
+	// Verify enables the verification of the upstream server certificate
+	Verify bool `json:"verify,omitempty" yaml:"verify,omitempty"`
+}
+
+// UpstreamKeepalivePool is the keepalive pool settings of an Upstream,
+// it overrides the global keepalive pool settings.
+type UpstreamKeepalivePool struct {
+	Size        int `json:"size,omitempty" yaml:"size,omitempty"`
+	IdleTimeout int `json:"idle_timeout,omitempty" yaml:"idle_timeout,omitempty"`
+	Requests    int `json:"requests,omitempty" yaml:"requests,omitempty"`
 }
 
 // UpstreamTimeout represents the timeout settings on Upstream.
@@ -315,6 +370,39 @@ type Consumer struct {
 
 	Plugins Plugins `json:"plugins,omitempty" yaml:"plugins,omitempty"`
 	GroupID string  `json:"group_id,omitempty" yaml:"group_id,omitempty"`
+
+	// Credentials are stored as child resources of the consumer,
+	// they are synced separately from the consumer itself.
+	Credentials []*Credential `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+}
+
+// WithoutCredentials returns a copy of the consumer without credentials,
+// since the credentials are synced and compared as child resources.
+func (c *Consumer) WithoutCredentials() *Consumer {
+	if c.Credentials == nil {
+		return c
+	}
+	consumer := *c
+	consumer.Credentials = nil
+	return &consumer
+}
+
+// Credential represents the credential of a consumer in APISIX.
+type Credential struct {
+	ID     string `json:"id" yaml:"id"`
+	Desc   string `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Labels Labels `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	Plugins Plugins `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+
+	// Consumer is the username of the consumer which owns the credential.
+	Consumer string `json:"-" yaml:"-"`
+}
+
+// ResourceKey returns the unique key of the credential,
+// it's the path relative to the consumers, e.g. "jack/credentials/key-1".
+func (c *Credential) ResourceKey() string {
+	return c.Consumer + "/credentials/" + c.ID
 }
 
 // SSL represents the ssl object in APISIX.
@@ -322,11 +410,38 @@ type SSL struct {
 	ID     string `json:"id" yaml:"id"`
 	Labels Labels `json:"labels,omitempty" yaml:"labels,omitempty"`
 
-	SNIs []string `json:"snis" yaml:"snis"`
+	// Type is "server" (default) for the certificates served to clients,
+	// or "client" for the certificates used in mTLS with the upstreams.
+	Type string   `json:"type,omitempty" yaml:"type,omitempty"`
+	SNIs []string `json:"snis,omitempty" yaml:"snis,omitempty"`
 	Cert string   `json:"cert,omitempty" yaml:"cert,omitempty"`
 	Key  string   `json:"key,omitempty" yaml:"key,omitempty"`
+	// Certs and Keys are the additional certificate pairs, e.g. RSA and ECC
+	// certificates of the same SNIs. They are paired by index.
+	Certs        []string   `json:"certs,omitempty" yaml:"certs,omitempty"`
+	Keys         []string   `json:"keys,omitempty" yaml:"keys,omitempty"`
+	Client       *SSLClient `json:"client,omitempty" yaml:"client,omitempty"`
+	SSLProtocols []string   `json:"ssl_protocols,omitempty" yaml:"ssl_protocols,omitempty"`
+
+	// Status enables (1) or disables (0) the SSL, it's a pointer
+	// so that a disabled SSL isn't omitted.
+	Status *int `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// SSLClient is the client verification settings of SSL, used in mTLS with clients.
+type SSLClient struct {
+	CA               string   `json:"ca,omitempty" yaml:"ca,omitempty"`
+	Depth            int      `json:"depth,omitempty" yaml:"depth,omitempty"`
+	SkipMtlsUriRegex []string `json:"skip_mtls_uri_regex,omitempty" yaml:"skip_mtls_uri_regex,omitempty"`
 }
 
+const (
+	// SSLTypeServer is the type of SSL served to clients
+	SSLTypeServer = "server"
+	// SSLTypeClient is the type of SSL used in mTLS with upstreams
+	SSLTypeClient = "client"
+)
+
 // GlobalRule represents the global_rule object in APISIX.
 type GlobalRule struct {
 	ID      string  `json:"id" yaml:"id"`
@@ -351,8 +466,40 @@ type ConsumerGroup struct {
 	Plugins Plugins `json:"plugins" yaml:"plugins"`
 }
 
+// Proto apisix proto object, used by the grpc-transcode plugin
+type Proto struct {
+	ID     string `json:"id" yaml:"id"`
+	Desc   string `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Labels Labels `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	Content string `json:"content,omitempty" yaml:"content,omitempty"`
+	// File is the path of a local .proto file, it's read and inlined
+	// into Content when loading the configuration file.
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+}
+
 const (
 	UpstreamPassHost = "host"
+
+	// UpstreamPassHostPass passes the client request host as is
+	UpstreamPassHostPass = "pass"
+	// UpstreamPassHostNode uses the host of the upstream node
+	UpstreamPassHostNode = "node"
+	// UpstreamPassHostRewrite uses the value of upstream_host
+	UpstreamPassHostRewrite = "rewrite"
+)
+
+const (
+	// UpstreamHashOnVars hashes on an NGINX variable
+	UpstreamHashOnVars = "vars"
+	// UpstreamHashOnHeader hashes on a request header
+	UpstreamHashOnHeader = "header"
+	// UpstreamHashOnCookie hashes on a cookie
+	UpstreamHashOnCookie = "cookie"
+	// UpstreamHashOnConsumer hashes on the consumer name
+	UpstreamHashOnConsumer = "consumer"
+	// UpstreamHashOnVarsCombinations hashes on a combination of NGINX variables
+	UpstreamHashOnVarsCombinations = "vars_combinations"
 )
 
 type PluginMetadata struct {
@@ -398,3 +545,61 @@ func (s *PluginMetadata) UnmarshalJSON(p []byte) error {
 
 	return nil
 }
+
+// Secret represents the secret manager object in APISIX,
+// which backs the `$secret://<manager>/<id>/...` references.
+type Secret struct {
+	ID      string                 `json:"id,omitempty" yaml:"id,omitempty"`
+	Manager string                 `json:"manager,omitempty" yaml:"manager,omitempty"`
+	Config  map[string]interface{} `json:",inline" yaml:",inline"`
+}
+
+// ResourceKey returns the unique key of the secret, since the secret
+// is addressed by both the manager type and the ID, e.g. "vault/1".
+func (s *Secret) ResourceKey() string {
+	return s.Manager + "/" + s.ID
+}
+
+func (s *Secret) MarshalJSON() ([]byte, error) {
+	config := make(map[string]interface{}, len(s.Config)+2)
+	for k, v := range s.Config {
+		config[k] = v
+	}
+	config["id"] = s.ID
+	config["manager"] = s.Manager
+
+	return json.Marshal(config)
+}
+
+func (s *Secret) UnmarshalJSON(p []byte) error {
+	var config map[string]interface{}
+
+	err := json.Unmarshal(p, &config)
+	if err != nil {
+		return err
+	}
+
+	if id, ok := config["id"]; ok {
+		if reflect.TypeOf(id).Kind() != reflect.String {
+			return errors.New("secret id is not a string, input: " + string(p))
+		}
+		s.ID = fmt.Sprintf("%v", id)
+		delete(config, "id")
+	}
+
+	if manager, ok := config["manager"]; ok {
+		if reflect.TypeOf(manager).Kind() != reflect.String {
+			return errors.New("secret manager is not a string, input: " + string(p))
+		}
+		s.Manager = fmt.Sprintf("%v", manager)
+		delete(config, "manager")
+	}
+
+	// the timestamps are managed by APISIX, not a part of the configuration
+	delete(config, "create_time")
+	delete(config, "update_time")
+
+	s.Config = config
+
+	return nil
+}
@@ -2,9 +2,11 @@ package types
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
 )
 
 func TestPluginMetadataMarshal(t *testing.T) {
@@ -35,3 +37,155 @@ func TestPluginMetadataMarshal(t *testing.T) {
 	assert.Equal(t, expectedConf["@timestamp"], unmarshalledConf["@timestamp"])
 	assert.Equal(t, expectedConf["client_ip"], unmarshalledConf["client_ip"])
 }
+
+func TestSecretMarshal(t *testing.T) {
+	secret := &Secret{
+		ID:      "1",
+		Manager: "vault",
+		Config: map[string]interface{}{
+			"uri":    "http://127.0.0.1:8200",
+			"prefix": "kv/apisix",
+			"token":  "root",
+		},
+	}
+
+	out, err := json.Marshal(secret)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"id":"1","manager":"vault","prefix":"kv/apisix","token":"root","uri":"http://127.0.0.1:8200"}`, string(out))
+	assert.Equal(t, "vault/1", secret.ResourceKey())
+
+	var unmarshalled Secret
+	err = json.Unmarshal(out, &unmarshalled)
+	assert.Nil(t, err)
+	assert.Equal(t, secret, &unmarshalled)
+}
+
+func TestSecretRoundTrip(t *testing.T) {
+	// the secret as returned by the Admin API
+	body := `{
+	"id": "1",
+	"manager": "vault",
+	"uri": "http://127.0.0.1:8200",
+	"prefix": "kv/apisix",
+	"token": "root",
+	"create_time": 1700000000,
+	"update_time": 1700000001
+}`
+
+	var secret Secret
+	err := json.Unmarshal([]byte(body), &secret)
+	assert.Nil(t, err, "check the unmarshal error")
+	assert.Equal(t, &Secret{
+		ID:      "1",
+		Manager: "vault",
+		Config: map[string]interface{}{
+			"uri":    "http://127.0.0.1:8200",
+			"prefix": "kv/apisix",
+			"token":  "root",
+		},
+	}, &secret, "check the server-managed fields are dropped")
+
+	out, err := json.Marshal(&secret)
+	assert.Nil(t, err, "check the marshal error")
+	assert.Equal(t, `{"id":"1","manager":"vault","prefix":"kv/apisix","token":"root","uri":"http://127.0.0.1:8200"}`, string(out), "check the marshalled secret")
+}
+
+// assertAllFieldsSet checks that every field of the struct is set,
+// so that the round trip tests cover the newly added fields.
+func assertAllFieldsSet(t *testing.T, obj interface{}) {
+	value := reflect.ValueOf(obj)
+	for i := 0; i < value.NumField(); i++ {
+		assert.False(t, value.Field(i).IsZero(), "field %s.%s should be set", value.Type().Name(), value.Type().Field(i).Name)
+	}
+}
+
+func TestRouteRoundTrip(t *testing.T) {
+	// the route as returned by the Admin API, with every field set
+	body := `{
+	"id": "route",
+	"name": "route",
+	"labels": {"team": "a"},
+	"desc": "route with all fields",
+	"host": "foo.com",
+	"hosts": ["foo.com", "bar.com"],
+	"uri": "/get",
+	"priority": 10,
+	"timeout": {"connect": 3, "send": 3, "read": 3},
+	"vars": [["arg_name", "==", "json"]],
+	"uris": ["/get", "/post"],
+	"methods": ["GET", "POST"],
+	"enable_websocket": true,
+	"remote_addr": "127.0.0.1",
+	"remote_addrs": ["127.0.0.1", "10.0.0.0/8"],
+	"upstream": {"id": "ups", "name": "ups", "type": "roundrobin", "nodes": [{"host": "httpbin.org", "port": 80, "weight": 1}]},
+	"upstream_id": "ups",
+	"service_id": "svc",
+	"plugins": {"not-a-real-plugin": {"key": "value"}},
+	"plugin_config_id": "pc",
+	"filter_func": "function(vars) return true end",
+	"script": "local _M = {} return _M",
+	"script_id": "script",
+	"service_protocol": "grpc",
+	"status": 0
+}`
+
+	var route Route
+	err := json.Unmarshal([]byte(body), &route)
+	assert.Nil(t, err)
+
+	assertAllFieldsSet(t, route)
+
+	out, err := yaml.Marshal(&route)
+	assert.Nil(t, err)
+
+	var unmarshalled Route
+	err = yaml.Unmarshal(out, &unmarshalled)
+	assert.Nil(t, err)
+	assert.Equal(t, route, unmarshalled)
+	assert.Equal(t, 0, *unmarshalled.Status, "disabled status should be kept")
+}
+
+func TestUpstreamRoundTrip(t *testing.T) {
+	// the upstream as returned by the Admin API, with every field set
+	body := `{
+	"id": "ups",
+	"name": "ups",
+	"desc": "upstream with all fields",
+	"labels": {"team": "a"},
+	"type": "chash",
+	"hash_on": "vars_combinations",
+	"key": "$remote_addr$http_user_agent",
+	"checks": {"active": {"type": "http", "http_path": "/status"}},
+	"nodes": [{"host": "httpbin.org", "port": 443, "weight": 1, "priority": 1, "metadata": {"zone": "a"}}],
+	"scheme": "https",
+	"retries": 2,
+	"retry_timeout": 5,
+	"timeout": {"connect": 3, "send": 3, "read": 3},
+	"tls": {"client_cert": "cert", "client_key": "key", "client_cert_id": "client-ssl", "verify": true},
+	"keepalive_pool": {"size": 320, "idle_timeout": 60, "requests": 1000},
+	"pass_host": "rewrite",
+	"upstream_host": "httpbin.org",
+	"service_name": "httpbin",
+	"discovery_type": "nacos",
+	"discovery_args": {"namespace_id": "public", "metadata": {"version": "v1"}}
+}`
+
+	var upstream Upstream
+	err := json.Unmarshal([]byte(body), &upstream)
+	assert.Nil(t, err)
+
+	assertAllFieldsSet(t, upstream)
+	assertAllFieldsSet(t, upstream.Nodes[0])
+	assertAllFieldsSet(t, *upstream.TLS)
+	assertAllFieldsSet(t, *upstream.KeepalivePool)
+
+	out, err := yaml.Marshal(&upstream)
+	assert.Nil(t, err)
+	assert.Contains(t, string(out), "pass_host: rewrite")
+
+	var unmarshalled Upstream
+	err = yaml.Unmarshal(out, &unmarshalled)
+	assert.Nil(t, err)
+	assert.Equal(t, upstream, unmarshalled)
+}
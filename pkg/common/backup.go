@@ -0,0 +1,75 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+const (
+	backupPrefix = "backup-"
+	backupSuffix = ".yaml"
+	// backupTimeFormat keeps the backups sorted by time when sorted by name
+	backupTimeFormat = "20060102-150405.000000"
+)
+
+// SaveBackup saves the configuration to a timestamped backup file in dir,
+// and removes the oldest backups so that at most retention backups are kept.
+// All backups are kept if retention isn't positive.
+func SaveBackup(dir string, retention int, conf *types.Configuration) (string, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return "", err
+	}
+
+	// the backups contain secrets, e.g. the SSL keys and consumer credentials,
+	// so they are only readable by the owner
+	path := filepath.Join(dir, backupPrefix+time.Now().UTC().Format(backupTimeFormat)+backupSuffix)
+	err = saveConfiguration(path, conf, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	if retention > 0 {
+		backups, err := ListBackups(dir)
+		if err != nil {
+			return "", err
+		}
+		for len(backups) > retention {
+			err = os.Remove(backups[0])
+			if err != nil {
+				return "", err
+			}
+			backups = backups[1:]
+		}
+	}
+
+	return path, nil
+}
+
+// ListBackups returns the paths of backups in dir, from the oldest to the newest.
+func ListBackups(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+		backups = append(backups, filepath.Join(dir, name))
+	}
+	sort.Strings(backups)
+
+	return backups, nil
+}
@@ -0,0 +1,55 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func TestSaveBackup(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	conf := &types.Configuration{
+		Services: []*types.Service{
+			{
+				ID:   "svc",
+				Name: "svc",
+			},
+		},
+	}
+
+	// Test case 1: no backups
+	backups, err := ListBackups(dir)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, 0, len(backups), "check the number of backups")
+
+	// Test case 2: the oldest backups are removed
+	var paths []string
+	for i := 0; i < 3; i++ {
+		path, err := SaveBackup(dir, 2, conf)
+		assert.Nil(t, err, "check the error")
+		paths = append(paths, path)
+	}
+	err = os.WriteFile(filepath.Join(dir, "other.yaml"), nil, 0o600)
+	assert.Nil(t, err, "check the error")
+
+	backups, err = ListBackups(dir)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, paths[1:], backups, "check the kept backups")
+
+	// Test case 3: the backup can be read as a configuration file
+	content, err := GetContentFromFile(backups[1])
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, conf.Services, content.Services, "check the content of backup")
+
+	// Test case 4: the backups are only accessible by the owner
+	info, err := os.Stat(dir)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, os.FileMode(0o700), info.Mode().Perm(), "check the permission of backup directory")
+	info, err = os.Stat(backups[1])
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "check the permission of backup file")
+}
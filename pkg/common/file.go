@@ -3,14 +3,17 @@ package common
 import (
 	"bufio"
 	"context"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/fatih/color"
 	"sigs.k8s.io/yaml"
 
 	"github.com/api7/adc/pkg/api/apisix"
 	"github.com/api7/adc/pkg/api/apisix/types"
+	"github.com/api7/adc/pkg/data"
 )
 
 func NormalizeConfiguration(content *types.Configuration) {
@@ -21,6 +24,9 @@ func NormalizeConfiguration(content *types.Configuration) {
 		if route.Name == "" {
 			route.Name = route.ID
 		}
+		if route.Upstream != nil && route.Upstream.ID == "" {
+			route.Upstream.ID = route.Upstream.Name
+		}
 	}
 
 	for _, service := range content.Services {
@@ -31,6 +37,27 @@ func NormalizeConfiguration(content *types.Configuration) {
 			service.Upstream.ID = service.Upstream.Name
 		}
 	}
+
+	for _, consumer := range content.Consumers {
+		for _, credential := range consumer.Credentials {
+			credential.Consumer = consumer.Username
+		}
+	}
+
+	for _, streamRoute := range content.StreamRoutes {
+		if streamRoute.Upstream != nil && streamRoute.Upstream.ID == "" {
+			streamRoute.Upstream.ID = streamRoute.Upstream.Name
+		}
+	}
+
+	for _, upstream := range content.Upstreams {
+		if upstream.ID == "" {
+			upstream.ID = upstream.Name
+		}
+		if upstream.Name == "" {
+			upstream.Name = upstream.ID
+		}
+	}
 }
 
 func GetContentFromFile(filename string) (*types.Configuration, error) {
@@ -57,79 +84,218 @@ func GetContentFromFile(filename string) (*types.Configuration, error) {
 		return nil, err
 	}
 
+	err = inlineProtoFiles(&content, filepath.Dir(filename))
+	if err != nil {
+		color.Red("Load proto files for %s failed: %s", filename, err)
+		return nil, err
+	}
+
 	NormalizeConfiguration(&content)
 
 	return &content, nil
 }
 
-func GetContentFromRemote(cluster apisix.Cluster) (*types.Configuration, error) {
-	svcs, err := cluster.Service().List(context.Background())
-	if err != nil {
-		return nil, err
+// inlineProtoFiles reads the local .proto files referenced by protos and
+// inlines them as the proto content. Relative paths are resolved from dir.
+func inlineProtoFiles(content *types.Configuration, dir string) error {
+	for _, proto := range content.Protos {
+		if proto.File == "" {
+			continue
+		}
+		if proto.Content != "" {
+			return fmt.Errorf("proto %s: content and file are mutually exclusive", proto.ID)
+		}
+
+		path := proto.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		proto.Content = string(content)
+		proto.File = ""
 	}
 
-	routes, err := cluster.Route().List(context.Background())
-	if err != nil {
-		return nil, err
+	return nil
+}
+
+// GetContentFromRemote fetches the configuration of the resource types selected
+// by the filter from APISIX, a nil filter selects all the resource types.
+func GetContentFromRemote(cluster apisix.Cluster, filter *data.ResourceTypeFilter) (*types.Configuration, error) {
+	var err error
+	conf := &types.Configuration{}
+
+	if filter.Enabled(data.ServiceResourceType) {
+		conf.Services, err = cluster.Service().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	consumers, err := cluster.Consumer().List(context.Background())
-	if err != nil {
-		return nil, err
+	if filter.Enabled(data.UpstreamResourceType) {
+		conf.Upstreams, err = cluster.Upstream().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	ssls, err := cluster.SSL().List(context.Background())
-	if err != nil {
-		return nil, err
+	if filter.Enabled(data.RouteResourceType) {
+		conf.Routes, err = cluster.Route().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	globalRules, err := cluster.GlobalRule().List(context.Background())
-	if err != nil {
-		return nil, err
+	if filter.Enabled(data.StreamRouteResourceType) {
+		conf.StreamRoutes, err = cluster.StreamRoute().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	pluginConfigs, err := cluster.PluginConfig().List(context.Background())
-	if err != nil {
-		return nil, err
+	// credentials are fetched with their consumers
+	if filter.Enabled(data.ConsumerResourceType) || filter.Enabled(data.CredentialResourceType) {
+		conf.Consumers, err = cluster.Consumer().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	consumerGroups, err := cluster.ConsumerGroup().List(context.Background())
-	if err != nil {
-		return nil, err
+	if filter.Enabled(data.SSLResourceType) {
+		conf.SSLs, err = cluster.SSL().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	pluginMetadatas, err := cluster.PluginMetadata().List(context.Background())
-	if err != nil {
-		return nil, err
+	if filter.Enabled(data.GlobalRuleResourceType) {
+		conf.GlobalRules, err = cluster.GlobalRule().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Enabled(data.PluginConfigResourceType) {
+		conf.PluginConfigs, err = cluster.PluginConfig().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Enabled(data.ConsumerGroupResourceType) {
+		conf.ConsumerGroups, err = cluster.ConsumerGroup().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Enabled(data.PluginMetadataResourceType) {
+		conf.PluginMetadatas, err = cluster.PluginMetadata().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Enabled(data.ProtoResourceType) {
+		conf.Protos, err = cluster.Proto().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Enabled(data.SecretResourceType) {
+		conf.Secrets, err = cluster.Secret().List(context.Background())
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return &types.Configuration{
-		Routes:          routes,
-		Services:        svcs,
-		Consumers:       consumers,
-		SSLs:            ssls,
-		GlobalRules:     globalRules,
-		PluginConfigs:   pluginConfigs,
-		ConsumerGroups:  consumerGroups,
-		PluginMetadatas: pluginMetadatas,
-	}, nil
+	return FilterResourceTypes(conf, filter), nil
+}
+
+// FilterResourceTypes returns a copy of the configuration without the resource
+// types which aren't selected by the filter.
+func FilterResourceTypes(conf *types.Configuration, filter *data.ResourceTypeFilter) *types.Configuration {
+	if filter == nil {
+		return conf
+	}
+
+	filtered := *conf
+	if !filter.Enabled(data.ServiceResourceType) {
+		filtered.Services = nil
+	}
+	if !filter.Enabled(data.UpstreamResourceType) {
+		filtered.Upstreams = nil
+	}
+	if !filter.Enabled(data.RouteResourceType) {
+		filtered.Routes = nil
+	}
+	if !filter.Enabled(data.StreamRouteResourceType) {
+		filtered.StreamRoutes = nil
+	}
+	if !filter.Enabled(data.SSLResourceType) {
+		filtered.SSLs = nil
+	}
+	if !filter.Enabled(data.GlobalRuleResourceType) {
+		filtered.GlobalRules = nil
+	}
+	if !filter.Enabled(data.PluginConfigResourceType) {
+		filtered.PluginConfigs = nil
+	}
+	if !filter.Enabled(data.ConsumerGroupResourceType) {
+		filtered.ConsumerGroups = nil
+	}
+	if !filter.Enabled(data.PluginMetadataResourceType) {
+		filtered.PluginMetadatas = nil
+	}
+	if !filter.Enabled(data.ProtoResourceType) {
+		filtered.Protos = nil
+	}
+	if !filter.Enabled(data.SecretResourceType) {
+		filtered.Secrets = nil
+	}
+
+	// consumers are kept for their credentials even if they aren't selected,
+	// the differ skips the events of them
+	switch {
+	case !filter.Enabled(data.ConsumerResourceType) && !filter.Enabled(data.CredentialResourceType):
+		filtered.Consumers = nil
+	case !filter.Enabled(data.CredentialResourceType):
+		filtered.Consumers = nil
+		for _, consumer := range conf.Consumers {
+			c := *consumer
+			c.Credentials = nil
+			filtered.Consumers = append(filtered.Consumers, &c)
+		}
+	}
+
+	return &filtered
 }
 
 func SaveAPISIXConfiguration(path string, conf *types.Configuration) error {
-	f, err := os.Create(path)
+	return saveConfiguration(path, conf, 0o666)
+}
+
+// saveConfiguration writes the configuration to the file, the file is
+// created with the permission perm (before umask) if it doesn't exist.
+func saveConfiguration(path string, conf *types.Configuration, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm)
 	if err != nil {
 		return err
 	}
 
 	defer f.Close()
 
-	data, err := yaml.Marshal(conf)
+	content, err := yaml.Marshal(conf)
 	if err != nil {
 		color.Red(err.Error())
 		return err
 	}
 
-	_, err = f.Write(data)
+	_, err = f.Write(content)
 	if err != nil {
 		return err
 	}
@@ -3,9 +3,13 @@ package common
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+	"github.com/api7/adc/pkg/data"
 )
 
 func TestGetContentFromFile(t *testing.T) {
@@ -39,4 +43,97 @@ services:
 	assert.Equal(t, "1.0.0", actualContent.Version)
 }
 
+func TestGetContentFromFileWithProtoFile(t *testing.T) {
+	dir := t.TempDir()
+
+	protoContent := `syntax = "proto3";
+package helloworld;
+service Greeter {
+    rpc SayHello (HelloRequest) returns (HelloReply) {}
+}
+message HelloRequest {
+    string name = 1;
+}
+message HelloReply {
+    string message = 1;
+}`
+	err := os.WriteFile(filepath.Join(dir, "helloworld.proto"), []byte(protoContent), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := `name: test
+version: "1.0.0"
+protos:
+- id: helloworld
+  file: helloworld.proto
+- id: inline
+  content: "syntax = \"proto3\";"
+`
+	configPath := filepath.Join(dir, "adc.yaml")
+	err = os.WriteFile(configPath, []byte(config), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := GetContentFromFile(configPath)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, 2, len(content.Protos), "check the number of protos")
+	assert.Equal(t, protoContent, content.Protos[0].Content, "check the inlined proto content")
+	assert.Equal(t, "", content.Protos[0].File, "check the proto file path is cleared")
+	assert.Equal(t, `syntax = "proto3";`, content.Protos[1].Content, "check the inline proto content")
+
+	// Test case 2: content and file are both set
+	config = `name: test
+version: "1.0.0"
+protos:
+- id: helloworld
+  file: helloworld.proto
+  content: "syntax = \"proto3\";"
+`
+	err = os.WriteFile(configPath, []byte(config), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = GetContentFromFile(configPath)
+	assert.NotNil(t, err, "check the error")
+}
+
+func TestFilterResourceTypes(t *testing.T) {
+	conf := &types.Configuration{
+		Routes: []*types.Route{
+			{
+				ID: "route",
+			},
+		},
+		Consumers: []*types.Consumer{
+			{
+				Username: "jack",
+				Credentials: []*types.Credential{
+					{
+						ID: "cred",
+					},
+				},
+			},
+		},
+	}
+
+	// Test case 1: nil filter selects all
+	assert.Equal(t, conf, FilterResourceTypes(conf, nil))
+
+	// Test case 2: consumers are kept without credentials
+	filter, _ := data.NewResourceTypeFilter([]string{"consumer"}, nil)
+	filtered := FilterResourceTypes(conf, filter)
+	assert.Nil(t, filtered.Routes, "check the routes")
+	assert.Equal(t, 1, len(filtered.Consumers), "check the number of consumers")
+	assert.Nil(t, filtered.Consumers[0].Credentials, "check the credentials")
+	assert.Equal(t, 1, len(conf.Consumers[0].Credentials), "the original configuration isn't changed")
+
+	// Test case 3: consumers are kept for credentials
+	filter, _ = data.NewResourceTypeFilter([]string{"credential"}, nil)
+	filtered = FilterResourceTypes(conf, filter)
+	assert.Equal(t, conf.Consumers, filtered.Consumers, "check the consumers")
+}
+
 // END: xz3c4v5b6n7m
@@ -0,0 +1,72 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+// ConfigNameLabel is the label of the configuration name stamped in the ownership mode,
+// so that resources synced from different configuration files don't delete each other.
+const ConfigNameLabel = "adc-config-name"
+
+// OwnerLabels returns the labels stamped on resources in the ownership mode,
+// which are the owner label in the form of key=value, and the configuration
+// name label if the name isn't empty.
+func OwnerLabels(ownerLabel, name string) (types.Labels, error) {
+	key, value, ok := strings.Cut(ownerLabel, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return nil, fmt.Errorf("invalid owner label '%s', it should be in the form of key=value", ownerLabel)
+	}
+
+	labels := types.Labels{key: strings.TrimSpace(value)}
+	if name != "" {
+		labels[ConfigNameLabel] = name
+	}
+	return labels, nil
+}
+
+// AddLabels adds the labels to all resources which have labels in the configuration.
+func AddLabels(conf *types.Configuration, labels types.Labels) {
+	add := func(resourceLabels *types.Labels) {
+		if *resourceLabels == nil {
+			*resourceLabels = make(types.Labels, len(labels))
+		}
+		for key, value := range labels {
+			(*resourceLabels)[key] = value
+		}
+	}
+
+	for _, route := range conf.Routes {
+		add(&route.Labels)
+	}
+	for _, streamRoute := range conf.StreamRoutes {
+		add(&streamRoute.Labels)
+	}
+	for _, service := range conf.Services {
+		add(&service.Labels)
+	}
+	for _, upstream := range conf.Upstreams {
+		add(&upstream.Labels)
+	}
+	for _, consumer := range conf.Consumers {
+		add(&consumer.Labels)
+		for _, credential := range consumer.Credentials {
+			add(&credential.Labels)
+		}
+	}
+	for _, ssl := range conf.SSLs {
+		add(&ssl.Labels)
+	}
+	for _, pluginConfig := range conf.PluginConfigs {
+		add(&pluginConfig.Labels)
+	}
+	for _, consumerGroup := range conf.ConsumerGroups {
+		add(&consumerGroup.Labels)
+	}
+	for _, proto := range conf.Protos {
+		add(&proto.Labels)
+	}
+}
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func TestOwnerLabels(t *testing.T) {
+	// Test case 1: with config name
+	labels, err := OwnerLabels("managed-by=adc", "team-a")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, types.Labels{"managed-by": "adc", ConfigNameLabel: "team-a"}, labels)
+
+	// Test case 2: without config name
+	labels, err = OwnerLabels("managed-by=adc", "")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, types.Labels{"managed-by": "adc"}, labels)
+
+	// Test case 3: invalid owner label
+	_, err = OwnerLabels("managed-by", "")
+	assert.EqualError(t, err, "invalid owner label 'managed-by', it should be in the form of key=value")
+}
+
+func TestAddLabels(t *testing.T) {
+	conf := &types.Configuration{
+		Routes: []*types.Route{
+			{
+				ID:     "route",
+				Labels: types.Labels{"team": "a"},
+			},
+		},
+		Consumers: []*types.Consumer{
+			{
+				Username: "jack",
+				Credentials: []*types.Credential{
+					{
+						ID: "cred",
+					},
+				},
+			},
+		},
+	}
+
+	AddLabels(conf, types.Labels{"managed-by": "adc"})
+	assert.Equal(t, types.Labels{"team": "a", "managed-by": "adc"}, conf.Routes[0].Labels, "check the labels of route")
+	assert.Equal(t, types.Labels{"managed-by": "adc"}, conf.Consumers[0].Labels, "check the labels of consumer")
+	assert.Equal(t, types.Labels{"managed-by": "adc"}, conf.Consumers[0].Credentials[0].Labels, "check the labels of credential")
+}
@@ -0,0 +1,96 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+// Selector selects the resources which carry all of its labels.
+type Selector map[string]string
+
+// ParseSelector parses the selector in the form of key=value[,key2=value2].
+func ParseSelector(s string) (Selector, error) {
+	selector := make(Selector)
+	if strings.TrimSpace(s) == "" {
+		return selector, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid selector '%s', it should be in the form of key=value[,key2=value2]", s)
+		}
+		selector[key] = strings.TrimSpace(value)
+	}
+
+	return selector, nil
+}
+
+// Matches returns true if the labels contain all labels of the selector.
+func (s Selector) Matches(labels types.Labels) bool {
+	for key, value := range s {
+		if v, ok := labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+func filterByLabels[T any](resources []*T, labels func(*T) types.Labels, selector Selector) []*T {
+	var filtered []*T
+	for _, resource := range resources {
+		if selector.Matches(labels(resource)) {
+			filtered = append(filtered, resource)
+		}
+	}
+	return filtered
+}
+
+// FilterConfiguration returns a copy of the configuration which only contains
+// the resources matched by the selector. Global rules, plugin metadatas and
+// secrets have no labels, so they are never selected by a non-empty selector.
+// The credentials are selected with their consumers, regardless of their own labels.
+func FilterConfiguration(conf *types.Configuration, selector Selector) *types.Configuration {
+	if len(selector) == 0 {
+		return conf
+	}
+
+	filtered := &types.Configuration{
+		Name:         conf.Name,
+		Version:      conf.Version,
+		IgnoreFields: conf.IgnoreFields,
+		Routes: filterByLabels(conf.Routes, func(r *types.Route) types.Labels {
+			return r.Labels
+		}, selector),
+		StreamRoutes: filterByLabels(conf.StreamRoutes, func(r *types.StreamRoute) types.Labels {
+			return r.Labels
+		}, selector),
+		Services: filterByLabels(conf.Services, func(s *types.Service) types.Labels {
+			return s.Labels
+		}, selector),
+		Upstreams: filterByLabels(conf.Upstreams, func(u *types.Upstream) types.Labels {
+			return u.Labels
+		}, selector),
+		SSLs: filterByLabels(conf.SSLs, func(s *types.SSL) types.Labels {
+			return s.Labels
+		}, selector),
+		PluginConfigs: filterByLabels(conf.PluginConfigs, func(p *types.PluginConfig) types.Labels {
+			return p.Labels
+		}, selector),
+		ConsumerGroups: filterByLabels(conf.ConsumerGroups, func(c *types.ConsumerGroup) types.Labels {
+			return c.Labels
+		}, selector),
+		Protos: filterByLabels(conf.Protos, func(p *types.Proto) types.Labels {
+			return p.Labels
+		}, selector),
+		// the credentials follow the selection of their consumers
+		Consumers: filterByLabels(conf.Consumers, func(c *types.Consumer) types.Labels {
+			return c.Labels
+		}, selector),
+	}
+
+	return filtered
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func TestParseSelector(t *testing.T) {
+	// Test case 1: empty selector
+	selector, err := ParseSelector("")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, Selector{}, selector)
+
+	// Test case 2: multiple labels
+	selector, err = ParseSelector("team=a, env=prod")
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, Selector{"team": "a", "env": "prod"}, selector)
+
+	// Test case 3: invalid selector
+	_, err = ParseSelector("team")
+	assert.EqualError(t, err, "invalid selector 'team', it should be in the form of key=value[,key2=value2]")
+}
+
+func TestFilterConfiguration(t *testing.T) {
+	conf := &types.Configuration{
+		Routes: []*types.Route{
+			{
+				ID:     "route1",
+				Labels: types.Labels{"team": "a", "env": "prod"},
+			},
+			{
+				ID:     "route2",
+				Labels: types.Labels{"team": "b"},
+			},
+			{
+				ID: "route3",
+			},
+		},
+		Consumers: []*types.Consumer{
+			{
+				Username: "jack",
+				Labels:   types.Labels{"team": "a"},
+				Credentials: []*types.Credential{
+					{
+						ID:     "cred1",
+						Labels: types.Labels{"team": "a"},
+					},
+					{
+						ID: "cred2",
+					},
+				},
+			},
+			{
+				Username: "tom",
+				Labels:   types.Labels{"team": "b"},
+				Credentials: []*types.Credential{
+					{
+						ID:     "cred3",
+						Labels: types.Labels{"team": "a"},
+					},
+				},
+			},
+		},
+		GlobalRules: []*types.GlobalRule{
+			{
+				ID: "rule",
+			},
+		},
+	}
+
+	// Test case 1: empty selector selects everything
+	assert.Equal(t, conf, FilterConfiguration(conf, Selector{}))
+
+	// Test case 2: only the matched resources are selected
+	filtered := FilterConfiguration(conf, Selector{"team": "a"})
+	assert.Equal(t, []*types.Route{conf.Routes[0]}, filtered.Routes, "check the selected routes")
+	assert.Equal(t, []*types.Consumer{conf.Consumers[0]}, filtered.Consumers, "check the credentials of other consumers aren't selected")
+	assert.Equal(t, conf.Consumers[0].Credentials, filtered.Consumers[0].Credentials, "check the credentials without labels are selected with the consumer")
+	assert.Nil(t, filtered.GlobalRules, "resources without labels aren't selected")
+}
@@ -0,0 +1,208 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// FieldAdded means the field only exists in the new value
+	FieldAdded = "added"
+	// FieldRemoved means the field only exists in the old value
+	FieldRemoved = "removed"
+	// FieldChanged means the field is different between the old and new value
+	FieldChanged = "changed"
+)
+
+// FieldChange is the change of a field between the old and new value,
+// Path is the JSON path of the field, e.g. plugins.limit-count.count.
+type FieldChange struct {
+	Path string      `json:"path"`
+	Type string      `json:"type"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// String returns the change in a single line, e.g.
+// plugins.limit-count.count: 100 → 200
+// upstream.nodes[1] removed
+func (c FieldChange) String() string {
+	switch c.Type {
+	case FieldAdded:
+		return fmt.Sprintf("%s added: %s", c.Path, formatValue(c.New))
+	case FieldRemoved:
+		return fmt.Sprintf("%s removed", c.Path)
+	default:
+		return fmt.Sprintf("%s: %s → %s", c.Path, formatValue(c.Old), formatValue(c.New))
+	}
+}
+
+// Changes returns the field-level changes of the update event,
+// and nil for the other events.
+func (e *Event) Changes() ([]FieldChange, error) {
+	if e.Option != UpdateOption {
+		return nil, nil
+	}
+
+	oldValue, err := toGeneric(e.OldValue)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := toGeneric(e.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffValues(nil, "", oldValue, newValue), nil
+}
+
+// toGeneric converts the value to the generic JSON form, so that the JSON
+// names of fields are used, and the omitted empty fields are ignored.
+func toGeneric(value interface{}) (interface{}, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	err = json.Unmarshal(content, &generic)
+	if err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// JoinPath appends the object key to the path, the keys which
+// can't be in a dot-notation path are quoted, e.g. labels["app.kubernetes.io/name"].
+func JoinPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]\"") || key == "" {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// IndexPath appends the array index to the path, e.g. nodes[0].
+func IndexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+// diffValues appends the changes between old and new under the path to changes
+func diffValues(changes []FieldChange, path string, oldValue, newValue interface{}) []FieldChange {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		return diffObjects(changes, path, oldMap, newMap)
+	}
+
+	oldSlice, oldIsSlice := oldValue.([]interface{})
+	newSlice, newIsSlice := newValue.([]interface{})
+	if oldIsSlice && newIsSlice {
+		return diffArrays(changes, path, oldSlice, newSlice)
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		changes = append(changes, FieldChange{Path: path, Type: FieldChanged, Old: oldValue, New: newValue})
+	}
+	return changes
+}
+
+func diffObjects(changes []FieldChange, path string, oldMap, newMap map[string]interface{}) []FieldChange {
+	keys := make([]string, 0, len(oldMap)+len(newMap))
+	for key := range oldMap {
+		keys = append(keys, key)
+	}
+	for key := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		oldValue, inOld := oldMap[key]
+		newValue, inNew := newMap[key]
+		switch {
+		case !inOld:
+			changes = append(changes, FieldChange{Path: JoinPath(path, key), Type: FieldAdded, New: newValue})
+		case !inNew:
+			changes = append(changes, FieldChange{Path: JoinPath(path, key), Type: FieldRemoved, Old: oldValue})
+		default:
+			changes = diffValues(changes, JoinPath(path, key), oldValue, newValue)
+		}
+	}
+	return changes
+}
+
+// diffArrays matches the equal elements of arrays by their longest common
+// subsequence, the unmatched elements between two matches are compared one
+// by one, and the rest of them are added or removed.
+func diffArrays(changes []FieldChange, path string, oldSlice, newSlice []interface{}) []FieldChange {
+	// lcs[i][j] is the length of LCS of oldSlice[i:] and newSlice[j:]
+	lcs := make([][]int, len(oldSlice)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newSlice)+1)
+	}
+	for i := len(oldSlice) - 1; i >= 0; i-- {
+		for j := len(newSlice) - 1; j >= 0; j-- {
+			if reflect.DeepEqual(oldSlice[i], newSlice[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var removed, added []int
+	flush := func() {
+		n := len(removed)
+		if len(added) < n {
+			n = len(added)
+		}
+		for k := 0; k < n; k++ {
+			changes = diffValues(changes, IndexPath(path, added[k]), oldSlice[removed[k]], newSlice[added[k]])
+		}
+		for _, i := range removed[n:] {
+			changes = append(changes, FieldChange{Path: IndexPath(path, i), Type: FieldRemoved, Old: oldSlice[i]})
+		}
+		for _, j := range added[n:] {
+			changes = append(changes, FieldChange{Path: IndexPath(path, j), Type: FieldAdded, New: newSlice[j]})
+		}
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(oldSlice) || j < len(newSlice) {
+		switch {
+		case i < len(oldSlice) && j < len(newSlice) && reflect.DeepEqual(oldSlice[i], newSlice[j]):
+			flush()
+			i++
+			j++
+		case j >= len(newSlice) || (i < len(oldSlice) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, i)
+			i++
+		default:
+			added = append(added, j)
+			j++
+		}
+	}
+	flush()
+
+	return changes
+}
+
+// formatValue formats the value of a field in a single line
+func formatValue(value interface{}) string {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(content)
+}
@@ -0,0 +1,56 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func TestUpstreamChanges(t *testing.T) {
+	oldUpstream := &types.Upstream{
+		ID: "upstream",
+		Labels: types.Labels{
+			"app.kubernetes.io/name": "httpbin",
+		},
+		Nodes: []types.UpstreamNode{
+			{Host: "10.0.0.1", Port: 80, Weight: 1},
+			{Host: "10.0.0.2", Port: 80, Weight: 1},
+			{Host: "10.0.0.3", Port: 80, Weight: 1},
+		},
+	}
+	newUpstream := &types.Upstream{
+		ID: "upstream",
+		Nodes: []types.UpstreamNode{
+			{Host: "10.0.0.1", Port: 80, Weight: 2},
+			{Host: "10.0.0.3", Port: 80, Weight: 1},
+		},
+	}
+
+	event := &Event{
+		ResourceType: UpstreamResourceType,
+		Option:       UpdateOption,
+		OldValue:     oldUpstream,
+		Value:        newUpstream,
+	}
+
+	changes, err := event.Changes()
+	assert.Nil(t, err, "check the error")
+	var lines []string
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+	assert.Equal(t, []string{
+		`labels removed`,
+		`nodes[0].weight: 1 → 2`,
+		`nodes[1] removed`,
+	}, lines, "check the changes")
+
+	// the keys which can't be in a dot-notation path are quoted
+	assert.Equal(t, `labels["app.kubernetes.io/name"]`, JoinPath("labels", "app.kubernetes.io/name"))
+
+	output, err := event.Output()
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, "updating upstream: \"upstream\"\n- labels removed\n~ nodes[0].weight: 1 → 2\n- nodes[1] removed", output)
+}
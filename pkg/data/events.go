@@ -4,10 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
-	"github.com/hexops/gotextdiff"
-	"github.com/hexops/gotextdiff/myers"
-	"github.com/hexops/gotextdiff/span"
 	"github.com/pkg/errors"
 
 	"github.com/api7/adc/pkg/api/apisix"
@@ -20,10 +18,16 @@ type ResourceType string
 var (
 	// ServiceResourceType is the resource type of service
 	ServiceResourceType ResourceType = "service"
+	// UpstreamResourceType is the resource type of upstream
+	UpstreamResourceType ResourceType = "upstream"
 	// RouteResourceType is the resource type of route
 	RouteResourceType ResourceType = "route"
+	// StreamRouteResourceType is the resource type of stream route
+	StreamRouteResourceType ResourceType = "stream_route"
 	// ConsumerResourceType is the resource type of consumer
 	ConsumerResourceType ResourceType = "consumer"
+	// CredentialResourceType is the resource type of consumer credential
+	CredentialResourceType ResourceType = "credential"
 	// SSLResourceType is the resource type of SSL
 	SSLResourceType ResourceType = "ssl"
 	// GlobalRuleResourceType is the resource type of global rule
@@ -34,6 +38,10 @@ var (
 	ConsumerGroupResourceType ResourceType = "consumer_group"
 	// PluginMetadataResourceType is the resource type of consumer group
 	PluginMetadataResourceType ResourceType = "plugin_metadata"
+	// ProtoResourceType is the resource type of proto
+	ProtoResourceType ResourceType = "proto"
+	// SecretResourceType is the resource type of secret
+	SecretResourceType ResourceType = "secret"
 )
 
 const (
@@ -53,38 +61,181 @@ type Event struct {
 	Value        interface{}  `json:"value"`
 }
 
+// Summary returns the one-line description of the event, e.g. creating route: "foo".
+func (e *Event) Summary() string {
+	switch e.Option {
+	case CreateOption:
+		return fmt.Sprintf("creating %s: \"%s\"", e.ResourceType, e.Key())
+	case DeleteOption:
+		return fmt.Sprintf("deleting %s: \"%s\"", e.ResourceType, e.Key())
+	default:
+		return fmt.Sprintf("updating %s: \"%s\"", e.ResourceType, e.Key())
+	}
+}
+
+// eventJSON is the serialized form of Event
+type eventJSON struct {
+	ResourceType ResourceType    `json:"resource_type"`
+	Option       int             `json:"option"`
+	OldValue     json.RawMessage `json:"old_value,omitempty"`
+	Value        json.RawMessage `json:"value,omitempty"`
+	// Consumer is the consumer of the credential, which isn't
+	// a part of the credential itself
+	Consumer string `json:"consumer,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e *Event) MarshalJSON() ([]byte, error) {
+	var err error
+	out := eventJSON{
+		ResourceType: e.ResourceType,
+		Option:       e.Option,
+	}
+	if e.OldValue != nil {
+		out.OldValue, err = json.Marshal(e.OldValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if e.Value != nil {
+		out.Value, err = json.Marshal(e.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, value := range []interface{}{e.Value, e.OldValue} {
+		if credential, ok := value.(*types.Credential); ok {
+			out.Consumer = credential.Consumer
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface,
+// Value and OldValue are decoded to the types of ResourceType.
+func (e *Event) UnmarshalJSON(p []byte) error {
+	var in eventJSON
+	err := json.Unmarshal(p, &in)
+	if err != nil {
+		return err
+	}
+
+	e.ResourceType = in.ResourceType
+	e.Option = in.Option
+	e.OldValue, err = unmarshalResource(in.ResourceType, in.OldValue, in.Consumer)
+	if err != nil {
+		return err
+	}
+	e.Value, err = unmarshalResource(in.ResourceType, in.Value, in.Consumer)
+	return err
+}
+
+// unmarshalResource decodes the resource to the type of typ, nil for an empty value.
+func unmarshalResource(typ ResourceType, p json.RawMessage, consumer string) (interface{}, error) {
+	if len(p) == 0 || string(p) == "null" {
+		return nil, nil
+	}
+
+	var resource interface{}
+	switch typ {
+	case ServiceResourceType:
+		resource = &types.Service{}
+	case UpstreamResourceType:
+		resource = &types.Upstream{}
+	case RouteResourceType:
+		resource = &types.Route{}
+	case StreamRouteResourceType:
+		resource = &types.StreamRoute{}
+	case ConsumerResourceType:
+		resource = &types.Consumer{}
+	case CredentialResourceType:
+		resource = &types.Credential{Consumer: consumer}
+	case SSLResourceType:
+		resource = &types.SSL{}
+	case GlobalRuleResourceType:
+		resource = &types.GlobalRule{}
+	case PluginConfigResourceType:
+		resource = &types.PluginConfig{}
+	case ConsumerGroupResourceType:
+		resource = &types.ConsumerGroup{}
+	case PluginMetadataResourceType:
+		resource = &types.PluginMetadata{}
+	case ProtoResourceType:
+		resource = &types.Proto{}
+	case SecretResourceType:
+		resource = &types.Secret{}
+	default:
+		return nil, fmt.Errorf("unknown resource type '%s'", typ)
+	}
+
+	err := json.Unmarshal(p, resource)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal "+string(typ))
+	}
+	return resource, nil
+}
+
+// changePrefixes are the line prefixes of field changes in the output
+var changePrefixes = map[string]string{
+	FieldAdded:   "+ ",
+	FieldRemoved: "- ",
+	FieldChanged: "~ ",
+}
+
 // Output returns the output of event,
 // if the event is create, it will return the message of creating resource.
-// if the event is update, it will return the diff of old value and new value.
+// if the event is update, it will return the field changes of old value and new value.
 // if the event is delete, it will return the message of deleting resource.
 func (e *Event) Output() (string, error) {
 	var output string
 	switch e.Option {
-	case CreateOption:
-		output = fmt.Sprintf("creating %s: \"%s\"", e.ResourceType, apisix.GetResourceUniqueKey(e.Value))
-	case DeleteOption:
-		output = fmt.Sprintf("deleting %s: \"%s\"", e.ResourceType, apisix.GetResourceUniqueKey(e.OldValue))
+	case CreateOption, DeleteOption:
+		output = e.Summary()
 	case UpdateOption:
-		remote, err := json.MarshalIndent(e.OldValue, "", "\t")
+		changes, err := e.Changes()
 		if err != nil {
 			return "", err
 		}
-		remote = append(remote, '\n')
 
-		local, err := json.MarshalIndent(e.Value, "", "\t")
-		if err != nil {
-			return "", err
+		lines := []string{e.Summary()}
+		for _, change := range changes {
+			lines = append(lines, changePrefixes[change.Type]+change.String())
 		}
-		local = append(local, '\n')
-
-		edits := myers.ComputeEdits(span.URIFromPath("remote"), string(remote), string(local))
-		diff := fmt.Sprint(gotextdiff.ToUnified("remote", "local", string(remote), edits))
-		output = fmt.Sprintf("updating %s: \"%s\"\n%s", e.ResourceType, apisix.GetResourceUniqueKey(e.Value), diff)
+		output = strings.Join(lines, "\n")
 	}
 
 	return output, nil
 }
 
+// Inverse returns the event which reverts the event,
+// the inverse of creating is deleting, the inverse of deleting is creating,
+// and the inverse of updating is updating back to the old value.
+func (e *Event) Inverse() *Event {
+	switch e.Option {
+	case CreateOption:
+		return &Event{
+			ResourceType: e.ResourceType,
+			Option:       DeleteOption,
+			OldValue:     e.Value,
+		}
+	case DeleteOption:
+		return &Event{
+			ResourceType: e.ResourceType,
+			Option:       CreateOption,
+			Value:        e.OldValue,
+		}
+	default:
+		return &Event{
+			ResourceType: e.ResourceType,
+			Option:       UpdateOption,
+			OldValue:     e.Value,
+			Value:        e.OldValue,
+		}
+	}
+}
+
 func apply[T any](client apisix.ResourceClient[T], event *Event) error {
 	var err error
 	switch event.Option {
@@ -103,14 +254,26 @@ func applyService(cluster apisix.Cluster, event *Event) error {
 	return apply[types.Service](cluster.Service(), event)
 }
 
+func applyUpstream(cluster apisix.Cluster, event *Event) error {
+	return apply[types.Upstream](cluster.Upstream(), event)
+}
+
 func applyRoute(cluster apisix.Cluster, event *Event) error {
 	return apply[types.Route](cluster.Route(), event)
 }
 
+func applyStreamRoute(cluster apisix.Cluster, event *Event) error {
+	return apply[types.StreamRoute](cluster.StreamRoute(), event)
+}
+
 func applyConsumer(cluster apisix.Cluster, event *Event) error {
 	return apply[types.Consumer](cluster.Consumer(), event)
 }
 
+func applyCredential(cluster apisix.Cluster, event *Event) error {
+	return apply[types.Credential](cluster.Consumer().Credential(), event)
+}
+
 func applySSL(cluster apisix.Cluster, event *Event) error {
 	return apply[types.SSL](cluster.SSL(), event)
 }
@@ -131,14 +294,28 @@ func applyPluginMetadata(cluster apisix.Cluster, event *Event) error {
 	return apply[types.PluginMetadata](cluster.PluginMetadata(), event)
 }
 
+func applyProto(cluster apisix.Cluster, event *Event) error {
+	return apply[types.Proto](cluster.Proto(), event)
+}
+
+func applySecret(cluster apisix.Cluster, event *Event) error {
+	return apply[types.Secret](cluster.Secret(), event)
+}
+
 func (e *Event) Apply(cluster apisix.Cluster) error {
 	switch e.ResourceType {
 	case ServiceResourceType:
 		return applyService(cluster, e)
+	case UpstreamResourceType:
+		return applyUpstream(cluster, e)
 	case RouteResourceType:
 		return applyRoute(cluster, e)
+	case StreamRouteResourceType:
+		return applyStreamRoute(cluster, e)
 	case ConsumerResourceType:
 		return applyConsumer(cluster, e)
+	case CredentialResourceType:
+		return applyCredential(cluster, e)
 	case SSLResourceType:
 		return applySSL(cluster, e)
 	case GlobalRuleResourceType:
@@ -149,6 +326,10 @@ func (e *Event) Apply(cluster apisix.Cluster) error {
 		return applyConsumerGroup(cluster, e)
 	case PluginMetadataResourceType:
 		return applyPluginMetadata(cluster, e)
+	case ProtoResourceType:
+		return applyProto(cluster, e)
+	case SecretResourceType:
+		return applySecret(cluster, e)
 	}
 
 	return nil
@@ -75,6 +75,47 @@ func TestEventOutput(t *testing.T) {
 	}
 	output, err = event.Output()
 	assert.Nil(t, err, "should not return error")
-	assert.Contains(t, output, "updating route: \"route\"", "should contain the route name")
-	assert.Contains(t, output, "+\t\"desc\": \"route1\"", "should contain the changes")
+	assert.Equal(t, "updating route: \"route\"\n+ desc added: \"route1\"", output, "should contain the changes")
+}
+
+func TestEventInverse(t *testing.T) {
+	// Test case 1: the inverse of creating is deleting
+	event := &Event{
+		ResourceType: ServiceResourceType,
+		Option:       CreateOption,
+		Value:        svc,
+	}
+	assert.Equal(t, &Event{
+		ResourceType: ServiceResourceType,
+		Option:       DeleteOption,
+		OldValue:     svc,
+	}, event.Inverse())
+
+	// Test case 2: the inverse of deleting is creating
+	event = &Event{
+		ResourceType: ServiceResourceType,
+		Option:       DeleteOption,
+		OldValue:     svc,
+	}
+	assert.Equal(t, &Event{
+		ResourceType: ServiceResourceType,
+		Option:       CreateOption,
+		Value:        svc,
+	}, event.Inverse())
+
+	// Test case 3: the inverse of updating is updating back
+	route1 := *route
+	route1.Description = "route1"
+	event = &Event{
+		ResourceType: RouteResourceType,
+		Option:       UpdateOption,
+		OldValue:     route,
+		Value:        &route1,
+	}
+	assert.Equal(t, &Event{
+		ResourceType: RouteResourceType,
+		Option:       UpdateOption,
+		OldValue:     &route1,
+		Value:        route,
+	}, event.Inverse())
 }
@@ -0,0 +1,213 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
+	"github.com/hexops/gotextdiff/span"
+
+	"github.com/api7/adc/pkg/api/apisix"
+)
+
+const (
+	// TextFormat is the colored text for terminals
+	TextFormat = "text"
+	// JSONFormat is the structured list of events
+	JSONFormat = "json"
+	// MarkdownFormat is the collapsible per-resource summary, e.g. for PR comments
+	MarkdownFormat = "markdown"
+	// PatchFormat is the unified patch of resources
+	PatchFormat = "patch"
+)
+
+// OutputFormats are all the output formats
+var OutputFormats = []string{TextFormat, JSONFormat, MarkdownFormat, PatchFormat}
+
+var optionNames = map[int]string{
+	CreateOption: "create",
+	DeleteOption: "delete",
+	UpdateOption: "update",
+}
+
+// eventOutput is the JSON output of an event
+type eventOutput struct {
+	ResourceType ResourceType  `json:"resource_type"`
+	Option       string        `json:"option"`
+	Key          string        `json:"key"`
+	Changes      []FieldChange `json:"changes,omitempty"`
+	Value        interface{}   `json:"value,omitempty"`
+	OldValue     interface{}   `json:"old_value,omitempty"`
+}
+
+// Key returns the unique key of the resource changed by the event.
+func (e *Event) Key() string {
+	if e.Option == DeleteOption {
+		return apisix.GetResourceUniqueKey(e.OldValue)
+	}
+	return apisix.GetResourceUniqueKey(e.Value)
+}
+
+// FormatEvents formats the events in the machine-readable format,
+// see the JSONFormat, MarkdownFormat and PatchFormat. The secret fields,
+// e.g. the SSL keys and consumer credentials, are redacted.
+func FormatEvents(events []*Event, format string) (string, error) {
+	switch format {
+	case JSONFormat:
+		return formatJSON(events)
+	case MarkdownFormat:
+		return formatMarkdown(events)
+	case PatchFormat:
+		return formatPatch(events)
+	}
+	return "", fmt.Errorf("unsupported output format '%s'", format)
+}
+
+func formatJSON(events []*Event) (string, error) {
+	outputs := make([]eventOutput, 0, len(events))
+	for _, event := range events {
+		changes, err := event.Changes()
+		if err != nil {
+			return "", err
+		}
+
+		output := eventOutput{
+			ResourceType: event.ResourceType,
+			Option:       optionNames[event.Option],
+			Key:          event.Key(),
+			Changes:      redactChanges(event.ResourceType, changes),
+		}
+		switch event.Option {
+		case CreateOption:
+			output.Value, err = redactValue(event.ResourceType, event.Value)
+		case DeleteOption:
+			output.OldValue, err = redactValue(event.ResourceType, event.OldValue)
+		}
+		if err != nil {
+			return "", err
+		}
+		outputs = append(outputs, output)
+	}
+
+	content, err := json.MarshalIndent(outputs, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(content) + "\n", nil
+}
+
+func formatMarkdown(events []*Event) (string, error) {
+	var created, updated, deleted int
+	for _, event := range events {
+		switch event.Option {
+		case CreateOption:
+			created++
+		case UpdateOption:
+			updated++
+		case DeleteOption:
+			deleted++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("### APISIX configuration changes\n\n")
+	fmt.Fprintf(&b, "Summary: created %d, updated %d, deleted %d\n", created, updated, deleted)
+
+	for _, event := range events {
+		fmt.Fprintf(&b, "\n<details>\n<summary>%s</summary>\n\n", event.Summary())
+		switch event.Option {
+		case UpdateOption:
+			changes, err := event.Changes()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString("| Field | Old | New |\n| --- | --- | --- |\n")
+			for _, change := range redactChanges(event.ResourceType, changes) {
+				fmt.Fprintf(&b, "| `%s` | %s | %s |\n", change.Path, markdownCell(change.Old), markdownCell(change.New))
+			}
+		default:
+			value := event.Value
+			if event.Option == DeleteOption {
+				value = event.OldValue
+			}
+			value, err := redactValue(event.ResourceType, value)
+			if err != nil {
+				return "", err
+			}
+			content, err := json.MarshalIndent(value, "", "  ")
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "```json\n%s\n```\n", content)
+		}
+		b.WriteString("\n</details>\n")
+	}
+
+	return b.String(), nil
+}
+
+// markdownCell formats the value in a markdown table cell
+func markdownCell(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(formatValue(value), "|", "\\|") + "`"
+}
+
+func formatPatch(events []*Event) (string, error) {
+	var b strings.Builder
+	for _, event := range events {
+		path := string(event.ResourceType) + "/" + event.Key()
+		from, to := "a/"+path, "b/"+path
+		switch event.Option {
+		case CreateOption:
+			from = "/dev/null"
+		case DeleteOption:
+			to = "/dev/null"
+		}
+
+		oldValue, err := redactValue(event.ResourceType, event.OldValue)
+		if err != nil {
+			return "", err
+		}
+		newValue, err := redactValue(event.ResourceType, event.Value)
+		if err != nil {
+			return "", err
+		}
+		diff, err := unifiedDiff(from, to, oldValue, newValue)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(diff)
+	}
+	return b.String(), nil
+}
+
+// unifiedDiff returns the unified diff of the indented JSON of old and new value,
+// a nil value is taken as empty.
+func unifiedDiff(from, to string, oldValue, newValue interface{}) (string, error) {
+	marshal := func(value interface{}) (string, error) {
+		if value == nil {
+			return "", nil
+		}
+		content, err := json.MarshalIndent(value, "", "\t")
+		if err != nil {
+			return "", err
+		}
+		return string(content) + "\n", nil
+	}
+
+	remote, err := marshal(oldValue)
+	if err != nil {
+		return "", err
+	}
+	local, err := marshal(newValue)
+	if err != nil {
+		return "", err
+	}
+
+	edits := myers.ComputeEdits(span.URIFromPath(from), remote, local)
+	return fmt.Sprint(gotextdiff.ToUnified(from, to, remote, edits)), nil
+}
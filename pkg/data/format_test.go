@@ -0,0 +1,130 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func newFormatTestEvents() []*Event {
+	oldRoute := &types.Route{
+		ID:   "route",
+		Name: "route",
+		Uri:  "/get",
+		Plugins: types.Plugins{
+			"my-limit": {
+				"count": 100,
+			},
+		},
+	}
+	newRoute := &types.Route{
+		ID:          "route",
+		Name:        "route",
+		Description: "a|b",
+		Uri:         "/get",
+		Plugins: types.Plugins{
+			"my-limit": {
+				"count": 200,
+			},
+		},
+	}
+
+	return []*Event{
+		{
+			ResourceType: ProtoResourceType,
+			Option:       CreateOption,
+			Value:        &types.Proto{ID: "proto", Content: "syntax = \"proto3\";"},
+		},
+		{
+			ResourceType: RouteResourceType,
+			Option:       UpdateOption,
+			OldValue:     oldRoute,
+			Value:        newRoute,
+		},
+	}
+}
+
+func TestEventChanges(t *testing.T) {
+	events := newFormatTestEvents()
+
+	// Test case 1: no changes for create events
+	changes, err := events[0].Changes()
+	assert.Nil(t, err, "check the error")
+	assert.Nil(t, changes, "check the changes")
+
+	// Test case 2: field-level changes for update events
+	changes, err = events[1].Changes()
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, []FieldChange{
+		{
+			Path: "desc",
+			Type: FieldAdded,
+			New:  "a|b",
+		},
+		{
+			Path: "plugins.my-limit.count",
+			Type: FieldChanged,
+			Old:  float64(100),
+			New:  float64(200),
+		},
+	}, changes, "check the changes")
+}
+
+func TestFormatEvents(t *testing.T) {
+	events := newFormatTestEvents()
+
+	// Test case 1: json
+	output, err := FormatEvents(events, JSONFormat)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, `[
+  {
+    "resource_type": "proto",
+    "option": "create",
+    "key": "proto",
+    "value": {
+      "id": "proto",
+      "content": "syntax = \"proto3\";"
+    }
+  },
+  {
+    "resource_type": "route",
+    "option": "update",
+    "key": "route",
+    "changes": [
+      {
+        "path": "desc",
+        "type": "added",
+        "new": "a|b"
+      },
+      {
+        "path": "plugins.my-limit.count",
+        "type": "changed",
+        "old": 100,
+        "new": 200
+      }
+    ]
+  }
+]
+`, output)
+
+	// Test case 2: markdown
+	output, err = FormatEvents(events, MarkdownFormat)
+	assert.Nil(t, err, "check the error")
+	assert.Contains(t, output, "Summary: created 1, updated 1, deleted 0\n")
+	assert.Contains(t, output, "<summary>creating proto: \"proto\"</summary>\n\n```json\n{\n  \"id\": \"proto\",")
+	assert.Contains(t, output, "| `desc` |  | `\"a\\|b\"` |\n")
+	assert.Contains(t, output, "| `plugins.my-limit.count` | `100` | `200` |\n")
+
+	// Test case 3: patch
+	output, err = FormatEvents(events, PatchFormat)
+	assert.Nil(t, err, "check the error")
+	assert.Contains(t, output, "--- /dev/null\n+++ b/proto/proto\n")
+	assert.Contains(t, output, "--- a/route/route\n+++ b/route/route\n")
+	assert.Contains(t, output, "+\t\"desc\": \"a|b\",\n")
+
+	// Test case 4: unsupported format
+	_, err = FormatEvents(events, "yaml")
+	assert.EqualError(t, err, "unsupported output format 'yaml'")
+}
@@ -0,0 +1,243 @@
+package data
+
+import (
+	"container/heap"
+	"errors"
+	"strconv"
+
+	"github.com/api7/adc/pkg/api/apisix"
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+// Graph is the dependency graph of events, the edges come from the
+// references between resources:
+// route references: service, upstream, plugin config, proto (grpc-transcode)
+// stream route references: service, upstream
+// service references: upstream
+// upstream references: SSL (tls.client_cert_id), also for the inline upstreams
+// consumer references: consumer group
+// credential references: consumer
+// A referenced resource is created/updated before, but deleted after the
+// resources which reference it. Secrets may be referenced by any resource
+// with `$secret://` values, so they are created/updated before and deleted
+// after all the others.
+type Graph struct {
+	events []*Event
+	// dependencies[i] are the events that must be applied before events[i]
+	dependencies [][]int
+	// dependents[i] are the events that wait for events[i]
+	dependents [][]int
+}
+
+// NewGraph builds the dependency graph of the events.
+func NewGraph(events []*Event) *Graph {
+	g := &Graph{
+		events:       events,
+		dependencies: make([][]int, len(events)),
+		dependents:   make([][]int, len(events)),
+	}
+
+	// resource key => index of the create/update or delete event
+	writes := make(map[string]int)
+	deletes := make(map[string]int)
+	var secretWrites, secretDeletes []int
+	for i, event := range events {
+		if event.Option == DeleteOption {
+			if event.OldValue != nil {
+				deletes[nodeKey(event.ResourceType, apisix.GetResourceUniqueKey(event.OldValue))] = i
+			}
+			if event.ResourceType == SecretResourceType {
+				secretDeletes = append(secretDeletes, i)
+			}
+		} else {
+			if event.Value != nil {
+				writes[nodeKey(event.ResourceType, apisix.GetResourceUniqueKey(event.Value))] = i
+			}
+			if event.ResourceType == SecretResourceType {
+				secretWrites = append(secretWrites, i)
+			}
+		}
+	}
+
+	for i, event := range events {
+		// the new references must exist before the event is applied
+		if event.Option != DeleteOption {
+			for _, ref := range references(event.Value) {
+				if j, ok := writes[ref]; ok {
+					g.addEdge(j, i)
+				}
+			}
+		}
+		// the old references can only be deleted after the event is applied
+		if event.Option != CreateOption {
+			for _, ref := range references(event.OldValue) {
+				if j, ok := deletes[ref]; ok {
+					g.addEdge(i, j)
+				}
+			}
+		}
+
+		if event.ResourceType == SecretResourceType {
+			continue
+		}
+		if event.Option != DeleteOption {
+			for _, j := range secretWrites {
+				g.addEdge(j, i)
+			}
+		}
+		for _, j := range secretDeletes {
+			g.addEdge(i, j)
+		}
+	}
+
+	return g
+}
+
+// addEdge makes events[to] wait for events[from].
+func (g *Graph) addEdge(from, to int) {
+	g.dependencies[to] = append(g.dependencies[to], from)
+	g.dependents[from] = append(g.dependents[from], to)
+}
+
+// Events returns the events of the graph.
+func (g *Graph) Events() []*Event {
+	return g.events
+}
+
+// Dependencies returns the indices of events which must be applied before events[i].
+func (g *Graph) Dependencies(i int) []int {
+	return g.dependencies[i]
+}
+
+// Dependents returns the indices of events which wait for events[i].
+func (g *Graph) Dependents(i int) []int {
+	return g.dependents[i]
+}
+
+// Sort returns the events in the order they can be applied one by one.
+// Among the events that are ready, creations go first, then updates and
+// deletions, and the original order is kept otherwise.
+func (g *Graph) Sort() ([]*Event, error) {
+	pending := make([]int, len(g.events))
+	ready := &eventQueue{events: g.events}
+	for i := range g.events {
+		pending[i] = len(g.dependencies[i])
+		if pending[i] == 0 {
+			ready.indices = append(ready.indices, i)
+		}
+	}
+	heap.Init(ready)
+
+	sorted := make([]*Event, 0, len(g.events))
+	for ready.Len() > 0 {
+		i := heap.Pop(ready).(int)
+		sorted = append(sorted, g.events[i])
+		for _, j := range g.dependents[i] {
+			pending[j]--
+			if pending[j] == 0 {
+				heap.Push(ready, j)
+			}
+		}
+	}
+
+	if len(sorted) != len(g.events) {
+		return nil, errors.New("circular dependency between events")
+	}
+	return sorted, nil
+}
+
+var optionRank = map[int]int{
+	CreateOption: 0,
+	UpdateOption: 1,
+	DeleteOption: 2,
+}
+
+// eventQueue is a priority queue of event indices, see Graph.Sort
+type eventQueue struct {
+	events  []*Event
+	indices []int
+}
+
+func (q *eventQueue) Len() int { return len(q.indices) }
+
+func (q *eventQueue) Less(i, j int) bool {
+	a, b := q.indices[i], q.indices[j]
+	rankA, rankB := optionRank[q.events[a].Option], optionRank[q.events[b].Option]
+	if rankA != rankB {
+		return rankA < rankB
+	}
+	return a < b
+}
+
+func (q *eventQueue) Swap(i, j int) { q.indices[i], q.indices[j] = q.indices[j], q.indices[i] }
+
+func (q *eventQueue) Push(x interface{}) { q.indices = append(q.indices, x.(int)) }
+
+func (q *eventQueue) Pop() interface{} {
+	n := len(q.indices)
+	x := q.indices[n-1]
+	q.indices = q.indices[:n-1]
+	return x
+}
+
+func nodeKey(typ ResourceType, key string) string {
+	return string(typ) + ":" + key
+}
+
+// references returns the keys of resources referenced by the resource.
+func references(resource interface{}) []string {
+	var refs []string
+	add := func(typ ResourceType, id string) {
+		if id != "" {
+			refs = append(refs, nodeKey(typ, id))
+		}
+	}
+
+	addUpstream := func(upstream *types.Upstream) {
+		if upstream != nil && upstream.TLS != nil {
+			add(SSLResourceType, upstream.TLS.CertID)
+		}
+	}
+
+	switch r := resource.(type) {
+	case *types.Route:
+		add(ServiceResourceType, r.ServiceID)
+		add(UpstreamResourceType, r.UpstreamId)
+		add(PluginConfigResourceType, r.PluginConfigId)
+		addUpstream(r.Upstream)
+		if transcode, ok := r.Plugins["grpc-transcode"]; ok {
+			add(ProtoResourceType, protoID(transcode["proto_id"]))
+		}
+	case *types.StreamRoute:
+		add(ServiceResourceType, r.ServiceID)
+		add(UpstreamResourceType, r.UpstreamId)
+		addUpstream(r.Upstream)
+	case *types.Service:
+		add(UpstreamResourceType, r.UpstreamId)
+		addUpstream(&r.Upstream)
+	case *types.Upstream:
+		addUpstream(r)
+	case *types.Consumer:
+		add(ConsumerGroupResourceType, r.GroupID)
+	case *types.Credential:
+		add(ConsumerResourceType, r.Consumer)
+	}
+
+	return refs
+}
+
+// protoID returns the proto ID referenced by grpc-transcode, APISIX accepts
+// both string and integer IDs.
+func protoID(id interface{}) string {
+	switch v := id.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return ""
+}
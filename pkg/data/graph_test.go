@@ -0,0 +1,149 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func TestGraph(t *testing.T) {
+	events := []*Event{
+		// 0: the consumer moves to a new consumer group
+		{
+			ResourceType: ConsumerResourceType,
+			Option:       UpdateOption,
+			OldValue:     &types.Consumer{Username: "jack", GroupID: "old"},
+			Value:        &types.Consumer{Username: "jack", GroupID: "new"},
+		},
+		// 1
+		{
+			ResourceType: ConsumerGroupResourceType,
+			Option:       DeleteOption,
+			OldValue:     &types.ConsumerGroup{ID: "old"},
+		},
+		// 2
+		{
+			ResourceType: ConsumerGroupResourceType,
+			Option:       CreateOption,
+			Value:        &types.ConsumerGroup{ID: "new"},
+		},
+		// 3
+		{
+			ResourceType: CredentialResourceType,
+			Option:       CreateOption,
+			Value:        &types.Credential{ID: "key", Consumer: "jack"},
+		},
+		// 4
+		{
+			ResourceType: RouteResourceType,
+			Option:       CreateOption,
+			Value: &types.Route{
+				ID: "route",
+				Plugins: types.Plugins{
+					"grpc-transcode": {
+						"proto_id": "proto",
+					},
+				},
+			},
+		},
+		// 5
+		{
+			ResourceType: ProtoResourceType,
+			Option:       CreateOption,
+			Value:        &types.Proto{ID: "proto"},
+		},
+	}
+
+	g := NewGraph(events)
+	assert.Equal(t, []int{2}, g.Dependencies(0), "consumer waits for the new consumer group")
+	assert.Equal(t, []int{0}, g.Dependencies(1), "old consumer group waits for the consumer")
+	assert.Equal(t, []int{0}, g.Dependencies(3), "credential waits for the consumer")
+	assert.Equal(t, []int{5}, g.Dependencies(4), "route waits for the proto")
+	assert.Equal(t, []int{0}, g.Dependents(2), "check the dependents of the new consumer group")
+
+	sorted, err := g.Sort()
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, []*Event{events[2], events[5], events[4], events[0], events[3], events[1]}, sorted, "check the sorted events")
+}
+
+func TestGraphReferences(t *testing.T) {
+	tls := &types.ClientTLS{CertID: "client"}
+	events := []*Event{
+		// 0
+		{
+			ResourceType: UpstreamResourceType,
+			Option:       CreateOption,
+			Value:        &types.Upstream{ID: "upstream", TLS: tls},
+		},
+		// 1
+		{
+			ResourceType: SSLResourceType,
+			Option:       CreateOption,
+			Value:        &types.SSL{ID: "client"},
+		},
+		// 2
+		{
+			ResourceType: RouteResourceType,
+			Option:       CreateOption,
+			Value:        &types.Route{ID: "route", Upstream: &types.Upstream{TLS: tls}},
+		},
+		// 3
+		{
+			ResourceType: ServiceResourceType,
+			Option:       CreateOption,
+			Value:        &types.Service{ID: "service", Upstream: types.Upstream{TLS: tls}},
+		},
+		// 4
+		{
+			ResourceType: StreamRouteResourceType,
+			Option:       CreateOption,
+			Value:        &types.StreamRoute{ID: "stream-route", Upstream: &types.Upstream{TLS: tls}},
+		},
+		// 5: the upstream stops using the old client certificate
+		{
+			ResourceType: UpstreamResourceType,
+			Option:       UpdateOption,
+			OldValue:     &types.Upstream{ID: "old", TLS: &types.ClientTLS{CertID: "old-client"}},
+			Value:        &types.Upstream{ID: "old"},
+		},
+		// 6
+		{
+			ResourceType: SSLResourceType,
+			Option:       DeleteOption,
+			OldValue:     &types.SSL{ID: "old-client"},
+		},
+		// 7: the proto is referenced by an integer ID
+		{
+			ResourceType: RouteResourceType,
+			Option:       CreateOption,
+			Value: &types.Route{
+				ID: "grpc",
+				Plugins: types.Plugins{
+					"grpc-transcode": {
+						"proto_id": float64(1),
+					},
+				},
+			},
+		},
+		// 8
+		{
+			ResourceType: ProtoResourceType,
+			Option:       CreateOption,
+			Value:        &types.Proto{ID: "1"},
+		},
+	}
+
+	g := NewGraph(events)
+	assert.Equal(t, []int{1}, g.Dependencies(0), "upstream waits for the client SSL")
+	assert.Equal(t, []int{1}, g.Dependencies(2), "route waits for the client SSL of the inline upstream")
+	assert.Equal(t, []int{1}, g.Dependencies(3), "service waits for the client SSL of the inline upstream")
+	assert.Equal(t, []int{1}, g.Dependencies(4), "stream route waits for the client SSL of the inline upstream")
+	assert.Equal(t, []int{5}, g.Dependencies(6), "old client SSL waits for the upstream")
+	assert.Equal(t, []int{8}, g.Dependencies(7), "route waits for the proto with the integer ID")
+
+	sorted, err := g.Sort()
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, []*Event{events[1], events[0], events[2], events[3], events[4], events[8], events[7], events[5], events[6]}, sorted, "check the sorted events")
+}
@@ -0,0 +1,106 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+// Plan is the set of events to apply, with the fingerprint of
+// the remote configuration which the events are computed against.
+type Plan struct {
+	Fingerprint string   `json:"fingerprint"`
+	Events      []*Event `json:"events"`
+}
+
+// NewPlan creates a plan of the events computed against the remote configuration.
+func NewPlan(events []*Event, remote *types.Configuration) (*Plan, error) {
+	fingerprint, err := Fingerprint(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{
+		Fingerprint: fingerprint,
+		Events:      events,
+	}, nil
+}
+
+// Fingerprint returns the SHA-256 hash of the configuration,
+// the same configuration always has the same fingerprint.
+func Fingerprint(conf *types.Configuration) (string, error) {
+	content, err := json.Marshal(conf)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Configuration returns the resources created or updated by the plan.
+func (p *Plan) Configuration() *types.Configuration {
+	conf := &types.Configuration{}
+	for _, event := range p.Events {
+		switch value := event.Value.(type) {
+		case *types.Service:
+			conf.Services = append(conf.Services, value)
+		case *types.Upstream:
+			conf.Upstreams = append(conf.Upstreams, value)
+		case *types.Route:
+			conf.Routes = append(conf.Routes, value)
+		case *types.StreamRoute:
+			conf.StreamRoutes = append(conf.StreamRoutes, value)
+		case *types.Consumer:
+			conf.Consumers = append(conf.Consumers, value)
+		case *types.Credential:
+			conf.Consumers = append(conf.Consumers, &types.Consumer{
+				Username:    value.Consumer,
+				Credentials: []*types.Credential{value},
+			})
+		case *types.SSL:
+			conf.SSLs = append(conf.SSLs, value)
+		case *types.GlobalRule:
+			conf.GlobalRules = append(conf.GlobalRules, value)
+		case *types.PluginConfig:
+			conf.PluginConfigs = append(conf.PluginConfigs, value)
+		case *types.ConsumerGroup:
+			conf.ConsumerGroups = append(conf.ConsumerGroups, value)
+		case *types.PluginMetadata:
+			conf.PluginMetadatas = append(conf.PluginMetadatas, value)
+		case *types.Proto:
+			conf.Protos = append(conf.Protos, value)
+		case *types.Secret:
+			conf.Secrets = append(conf.Secrets, value)
+		}
+	}
+	return conf
+}
+
+// Save writes the plan to the file.
+func (p *Plan) Save(path string) error {
+	content, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// ReadPlan reads the plan from the file.
+func ReadPlan(path string) (*Plan, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan Plan
+	err = json.Unmarshal(content, &plan)
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
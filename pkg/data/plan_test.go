@@ -0,0 +1,85 @@
+package data
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func TestPlan(t *testing.T) {
+	route1 := *route
+	route1.Description = "route1"
+	credential := &types.Credential{
+		ID:       "cred",
+		Consumer: "jack",
+		Plugins: types.Plugins{
+			"my-auth": {
+				"key": "auth-one",
+			},
+		},
+	}
+	secret := &types.Secret{
+		ID:      "vault-1",
+		Manager: "vault",
+		Config: map[string]interface{}{
+			"uri": "http://127.0.0.1:8200",
+		},
+	}
+	remote := &types.Configuration{
+		Services: []*types.Service{svc},
+		Routes:   []*types.Route{route},
+	}
+
+	plan, err := NewPlan([]*Event{
+		{
+			ResourceType: ServiceResourceType,
+			Option:       DeleteOption,
+			OldValue:     svc,
+		},
+		{
+			ResourceType: RouteResourceType,
+			Option:       UpdateOption,
+			OldValue:     route,
+			Value:        &route1,
+		},
+		{
+			ResourceType: CredentialResourceType,
+			Option:       CreateOption,
+			Value:        credential,
+		},
+		{
+			ResourceType: SecretResourceType,
+			Option:       CreateOption,
+			Value:        secret,
+		},
+	}, remote)
+	assert.Nil(t, err, "check the error")
+
+	// Test case 1: the events are read back with typed values
+	path := filepath.Join(t.TempDir(), "plan.json")
+	err = plan.Save(path)
+	assert.Nil(t, err, "check the error")
+
+	read, err := ReadPlan(path)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, plan, read, "check the plan read back")
+
+	// Test case 2: the fingerprint changes with the remote configuration
+	fingerprint, err := Fingerprint(remote)
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, plan.Fingerprint, fingerprint, "check the same fingerprint")
+
+	remote.Routes = []*types.Route{&route1}
+	fingerprint, err = Fingerprint(remote)
+	assert.Nil(t, err, "check the error")
+	assert.NotEqual(t, plan.Fingerprint, fingerprint, "check the changed fingerprint")
+
+	// Test case 3: the configuration of created and updated resources
+	conf := plan.Configuration()
+	assert.Equal(t, []*types.Route{&route1}, conf.Routes, "check the routes")
+	assert.Nil(t, conf.Services, "check the services")
+	assert.Equal(t, []*types.Credential{credential}, conf.Consumers[0].Credentials, "check the credentials")
+}
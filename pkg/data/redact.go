@@ -0,0 +1,112 @@
+package data
+
+import (
+	"strings"
+)
+
+// RedactedValue replaces the values of secret fields in the outputs
+const RedactedValue = "[redacted]"
+
+// authSecretFields are the secret fields of the auth plugins on consumers and credentials
+var authSecretFields = []string{
+	"plugins.key-auth.key",
+	"plugins.basic-auth.password",
+	"plugins.jwt-auth.secret",
+	"plugins.jwt-auth.private_key",
+	"plugins.hmac-auth.secret_key",
+}
+
+// secretFields are the paths of secret fields of each resource type, they are
+// redacted in the json, markdown and patch outputs which may be posted in PR comments.
+var secretFields = map[ResourceType][]string{
+	SSLResourceType:         {"key", "keys"},
+	ConsumerResourceType:    authSecretFields,
+	CredentialResourceType:  authSecretFields,
+	SecretResourceType:      {"token", "secret_access_key", "session_token", "auth_config.private_key"},
+	UpstreamResourceType:    {"tls.client_key"},
+	RouteResourceType:       {"upstream.tls.client_key"},
+	ServiceResourceType:     {"upstream.tls.client_key"},
+	StreamRouteResourceType: {"upstream.tls.client_key"},
+}
+
+// redactValue returns the value with the secret fields of the resource type redacted,
+// or the value as it is if it has no secret fields.
+func redactValue(resourceType ResourceType, value interface{}) (interface{}, error) {
+	fields, ok := secretFields[resourceType]
+	if !ok || value == nil {
+		return value, nil
+	}
+
+	generic, err := toGeneric(value)
+	if err != nil {
+		return nil, err
+	}
+	redacted := false
+	for _, field := range fields {
+		redacted = redactField(generic, strings.Split(field, ".")) || redacted
+	}
+	if !redacted {
+		return value, nil
+	}
+	return generic, nil
+}
+
+// redactField redacts the field under the keys of the generic value,
+// and returns true if the field exists.
+func redactField(value interface{}, keys []string) bool {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	field, ok := object[keys[0]]
+	if !ok {
+		return false
+	}
+	if len(keys) > 1 {
+		return redactField(field, keys[1:])
+	}
+
+	object[keys[0]] = redact(field)
+	return true
+}
+
+// redact replaces the strings in the value, except the references
+// to secrets and environment variables which are not secret themselves.
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, "$secret://") || strings.HasPrefix(v, "$env://") {
+			return v
+		}
+		return RedactedValue
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redact(item)
+		}
+		return redacted
+	case nil:
+		return nil
+	}
+	return RedactedValue
+}
+
+// redactChanges redacts the old and new values of the changes of secret fields,
+// or of the objects containing secret fields, e.g. a newly added plugin.
+func redactChanges(resourceType ResourceType, changes []FieldChange) []FieldChange {
+	for i, change := range changes {
+		for _, field := range secretFields[resourceType] {
+			switch {
+			case change.Path == field || strings.HasPrefix(change.Path, field+".") || strings.HasPrefix(change.Path, field+"["):
+				changes[i].Old = redact(change.Old)
+				changes[i].New = redact(change.New)
+			case strings.HasPrefix(field, change.Path+"."):
+				// the values are generic, it's safe to change them
+				keys := strings.Split(strings.TrimPrefix(field, change.Path+"."), ".")
+				redactField(change.Old, keys)
+				redactField(change.New, keys)
+			}
+		}
+	}
+	return changes
+}
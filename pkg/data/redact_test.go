@@ -0,0 +1,105 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/api7/adc/pkg/api/apisix/types"
+)
+
+func TestRedact(t *testing.T) {
+	ssl := &types.SSL{
+		ID:   "ssl",
+		Cert: "cert",
+		Key:  "private-key",
+		Keys: []string{"private-key-1", "$secret://vault/1/ssl/key"},
+		SNIs: []string{"example.com"},
+	}
+	credential := &types.Credential{
+		ID:       "key-1",
+		Consumer: "jack",
+		Plugins: types.Plugins{
+			"key-auth": {
+				"key": "auth-one",
+			},
+		},
+	}
+	updatedCredential := &types.Credential{
+		ID:       "key-1",
+		Consumer: "jack",
+		Plugins: types.Plugins{
+			"key-auth": {
+				"key": "auth-two",
+			},
+			"basic-auth": {
+				"username": "jack",
+				"password": "$env://JACK_PASSWORD",
+			},
+		},
+	}
+	events := []*Event{
+		{
+			ResourceType: SSLResourceType,
+			Option:       CreateOption,
+			Value:        ssl,
+		},
+		{
+			ResourceType: CredentialResourceType,
+			Option:       UpdateOption,
+			OldValue:     credential,
+			Value:        updatedCredential,
+		},
+		{
+			ResourceType: ConsumerResourceType,
+			Option:       DeleteOption,
+			OldValue: &types.Consumer{
+				Username: "tom",
+				Plugins:  types.Plugins{"basic-auth": {"username": "tom", "password": "tom-password"}},
+			},
+		},
+	}
+
+	// Test case 1: the secret values and changes are redacted in all formats
+	for _, format := range []string{JSONFormat, MarkdownFormat, PatchFormat} {
+		output, err := FormatEvents(events, format)
+		assert.Nil(t, err, "check the error")
+		for _, secret := range []string{"private-key", "auth-one", "auth-two", "tom-password"} {
+			assert.NotContains(t, output, secret, "check the secret is redacted in "+format)
+		}
+		assert.Contains(t, output, RedactedValue, "check the redacted value in "+format)
+		assert.Contains(t, output, "$secret://vault/1/ssl/key", "check the secret reference is kept in "+format)
+		assert.Contains(t, output, "$env://JACK_PASSWORD", "check the env reference is kept in "+format)
+		assert.Contains(t, output, "example.com", "check the other fields are kept in "+format)
+	}
+
+	// Test case 2: the changes of secret fields are kept with the redacted values
+	changes, err := events[1].Changes()
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, []FieldChange{
+		{
+			Path: "plugins.basic-auth",
+			Type: FieldAdded,
+			New:  map[string]interface{}{"username": "jack", "password": "$env://JACK_PASSWORD"},
+		},
+		{
+			Path: "plugins.key-auth.key",
+			Type: FieldChanged,
+			Old:  RedactedValue,
+			New:  RedactedValue,
+		},
+	}, redactChanges(CredentialResourceType, changes), "check the redacted changes")
+
+	// Test case 3: the resources without secret fields are kept as they are
+	proto := &types.Proto{ID: "proto"}
+	value, err := redactValue(ProtoResourceType, proto)
+	assert.Nil(t, err, "check the error")
+	assert.Same(t, proto, value, "check the proto")
+	value, err = redactValue(SSLResourceType, &types.SSL{ID: "ssl"})
+	assert.Nil(t, err, "check the error")
+	assert.Equal(t, &types.SSL{ID: "ssl"}, value, "check the ssl without keys")
+
+	// Test case 4: the original resources aren't changed
+	assert.Equal(t, "private-key", ssl.Key, "check the original ssl")
+	assert.Equal(t, "auth-two", updatedCredential.Plugins["key-auth"]["key"], "check the original credential")
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResourceTypes are all the resource types
+var ResourceTypes = []ResourceType{
+	ServiceResourceType,
+	UpstreamResourceType,
+	RouteResourceType,
+	StreamRouteResourceType,
+	ConsumerResourceType,
+	CredentialResourceType,
+	SSLResourceType,
+	GlobalRuleResourceType,
+	PluginConfigResourceType,
+	ConsumerGroupResourceType,
+	PluginMetadataResourceType,
+	ProtoResourceType,
+	SecretResourceType,
+}
+
+// ResourceTypeFilter selects the resource types to handle,
+// a nil filter selects all the resource types.
+type ResourceTypeFilter struct {
+	include map[ResourceType]bool
+	exclude map[ResourceType]bool
+}
+
+// NewResourceTypeFilter creates a filter which only selects the included
+// resource types (all if include is empty) except the excluded ones.
+func NewResourceTypeFilter(include, exclude []string) (*ResourceTypeFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+
+	var err error
+	f := &ResourceTypeFilter{}
+	f.include, err = parseResourceTypes(include)
+	if err != nil {
+		return nil, err
+	}
+	f.exclude, err = parseResourceTypes(exclude)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func parseResourceTypes(values []string) (map[ResourceType]bool, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[ResourceType]bool, len(ResourceTypes))
+	for _, typ := range ResourceTypes {
+		known[typ] = true
+	}
+
+	types := make(map[ResourceType]bool, len(values))
+	for _, value := range values {
+		typ := ResourceType(strings.TrimSpace(value))
+		if !known[typ] {
+			return nil, fmt.Errorf("unknown resource type '%s'", value)
+		}
+		types[typ] = true
+	}
+	return types, nil
+}
+
+// Enabled returns true if the resource type is selected.
+func (f *ResourceTypeFilter) Enabled(typ ResourceType) bool {
+	if f == nil {
+		return true
+	}
+	if f.include != nil && !f.include[typ] {
+		return false
+	}
+	return !f.exclude[typ]
+}
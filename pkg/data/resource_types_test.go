@@ -0,0 +1,31 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceTypeFilter(t *testing.T) {
+	// Test case 1: nil filter selects all
+	f, err := NewResourceTypeFilter(nil, nil)
+	assert.Nil(t, err, "check the error")
+	assert.True(t, f.Enabled(RouteResourceType))
+
+	// Test case 2: include and exclude
+	f, err = NewResourceTypeFilter([]string{"route", "service"}, []string{"service"})
+	assert.Nil(t, err, "check the error")
+	assert.True(t, f.Enabled(RouteResourceType))
+	assert.False(t, f.Enabled(ServiceResourceType))
+	assert.False(t, f.Enabled(ConsumerResourceType))
+
+	// Test case 3: exclude only
+	f, err = NewResourceTypeFilter(nil, []string{"consumer"})
+	assert.Nil(t, err, "check the error")
+	assert.True(t, f.Enabled(RouteResourceType))
+	assert.False(t, f.Enabled(ConsumerResourceType))
+
+	// Test case 4: unknown resource type
+	_, err = NewResourceTypeFilter([]string{"routes"}, nil)
+	assert.EqualError(t, err, "unknown resource type 'routes'")
+}
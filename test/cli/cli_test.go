@@ -12,6 +12,9 @@ import (
 	_ "github.com/api7/adc/test/cli/suites-global-rule"
 	_ "github.com/api7/adc/test/cli/suites-plugin-config"
 	_ "github.com/api7/adc/test/cli/suites-plugin-metadata"
+	_ "github.com/api7/adc/test/cli/suites-proto"
+	_ "github.com/api7/adc/test/cli/suites-secret"
+	_ "github.com/api7/adc/test/cli/suites-upstream"
 	_ "github.com/api7/adc/test/cli/suites-usecase"
 )
 
@@ -0,0 +1,110 @@
+package proto
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/api7/adc/pkg/api/apisix"
+	"github.com/api7/adc/pkg/api/apisix/types"
+	"github.com/api7/adc/test/scaffold"
+)
+
+const helloworld = `syntax = "proto3";
+package helloworld;
+service Greeter {
+  rpc SayHello (HelloRequest) returns (HelloReply) {}
+}
+message HelloRequest {
+  string name = 1;
+}
+message HelloReply {
+  string message = 1;
+}`
+
+var _ = ginkgo.Describe("adc APISIX proto SDK tests", func() {
+	ginkgo.Context("Basic functions", func() {
+		s := scaffold.NewScaffold()
+		ginkgo.It("Proto resource", func() {
+			var (
+				err   error
+				proto *types.Proto
+			)
+
+			// utils
+			assertProtoEqual := func(expect, toBe *types.Proto) {
+				gomega.Expect(expect.ID).To(gomega.Equal(toBe.ID))
+				gomega.Expect(expect.Desc).To(gomega.Equal(toBe.Desc))
+				gomega.Expect(expect.Content).To(gomega.Equal(toBe.Content))
+			}
+
+			// create proto 1
+			baseProto1 := &types.Proto{
+				ID:      "proto1",
+				Content: helloworld,
+			}
+			_, err = s.CreateProto(baseProto1)
+			gomega.Expect(err).To(gomega.BeNil(), "error while creating proto")
+
+			// get proto 1
+			proto, err = s.GetProto("proto1")
+			gomega.Expect(err).To(gomega.BeNil())
+			assertProtoEqual(proto, baseProto1)
+
+			// create proto 2
+			baseProto2 := &types.Proto{
+				ID:      "proto2",
+				Desc:    "greeter",
+				Content: helloworld,
+			}
+			proto, err = s.CreateProto(baseProto2)
+			gomega.Expect(err).To(gomega.BeNil())
+			assertProtoEqual(proto, baseProto2)
+
+			// test list
+			protos, err := s.ListProto()
+			gomega.Expect(err).To(gomega.BeNil())
+			gomega.Expect(len(protos)).To(gomega.Equal(2))
+			var proto1, proto2 *types.Proto
+			for _, p := range protos {
+				if p.ID == "proto1" {
+					proto1 = p
+				} else if p.ID == "proto2" {
+					proto2 = p
+				}
+			}
+			gomega.Expect(proto1).NotTo(gomega.BeNil())
+			gomega.Expect(proto2).NotTo(gomega.BeNil())
+
+			assertProtoEqual(proto1, baseProto1)
+			assertProtoEqual(proto2, baseProto2)
+
+			// update & get proto 1
+			baseProto1.Desc = "hello world"
+			_, err = s.UpdateProto(baseProto1)
+			gomega.Expect(err).To(gomega.BeNil())
+
+			proto, err = s.GetProto("proto1")
+			gomega.Expect(err).To(gomega.BeNil())
+			assertProtoEqual(proto, baseProto1)
+
+			// delete proto 2
+			err = s.DeleteProto("proto2")
+			gomega.Expect(err).To(gomega.BeNil())
+
+			_, err = s.GetProto("proto2")
+			gomega.Expect(err).To(gomega.Equal(apisix.ErrNotFound))
+
+			// delete proto 1
+			err = s.DeleteProto("proto1")
+			gomega.Expect(err).To(gomega.BeNil())
+
+			_, err = s.GetProto("proto1")
+			gomega.Expect(err).To(gomega.Equal(apisix.ErrNotFound))
+
+			// final list
+			protos, err = s.ListProto()
+			gomega.Expect(err).To(gomega.BeNil())
+			gomega.Expect(len(protos)).To(gomega.Equal(0))
+		})
+	})
+})
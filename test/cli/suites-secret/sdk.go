@@ -0,0 +1,108 @@
+package secret
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/api7/adc/pkg/api/apisix"
+	"github.com/api7/adc/pkg/api/apisix/types"
+	"github.com/api7/adc/test/scaffold"
+)
+
+var _ = ginkgo.Describe("adc APISIX secret SDK tests", func() {
+	ginkgo.Context("Basic functions", func() {
+		s := scaffold.NewScaffold()
+		ginkgo.It("Secret resource", func() {
+			var (
+				err    error
+				secret *types.Secret
+			)
+
+			// utils
+			assertSecretEqual := func(expect, toBe *types.Secret) {
+				gomega.Expect(expect.ID).To(gomega.Equal(toBe.ID))
+				gomega.Expect(expect.Manager).To(gomega.Equal(toBe.Manager))
+				gomega.Expect(expect.Config["uri"]).To(gomega.Equal(toBe.Config["uri"]))
+				gomega.Expect(expect.Config["prefix"]).To(gomega.Equal(toBe.Config["prefix"]))
+			}
+
+			// create secret 1
+			baseSecret1 := &types.Secret{
+				ID:      "secret1",
+				Manager: "vault",
+				Config: map[string]interface{}{
+					"uri":    "http://127.0.0.1:8200",
+					"prefix": "kv/apisix",
+					"token":  "root",
+				},
+			}
+			_, err = s.CreateSecret(baseSecret1)
+			gomega.Expect(err).To(gomega.BeNil(), "error while creating secret")
+
+			// get secret 1
+			secret, err = s.GetSecret("vault/secret1")
+			gomega.Expect(err).To(gomega.BeNil())
+			assertSecretEqual(secret, baseSecret1)
+
+			// create secret 2
+			baseSecret2 := &types.Secret{
+				ID:      "secret2",
+				Manager: "vault",
+				Config: map[string]interface{}{
+					"uri":    "http://127.0.0.1:8200",
+					"prefix": "kv/team",
+					"token":  "root",
+				},
+			}
+			secret, err = s.CreateSecret(baseSecret2)
+			gomega.Expect(err).To(gomega.BeNil())
+			assertSecretEqual(secret, baseSecret2)
+
+			// test list
+			secrets, err := s.ListSecret()
+			gomega.Expect(err).To(gomega.BeNil())
+			gomega.Expect(len(secrets)).To(gomega.Equal(2))
+			var secret1, secret2 *types.Secret
+			for _, sec := range secrets {
+				if sec.ResourceKey() == "vault/secret1" {
+					secret1 = sec
+				} else if sec.ResourceKey() == "vault/secret2" {
+					secret2 = sec
+				}
+			}
+			gomega.Expect(secret1).NotTo(gomega.BeNil())
+			gomega.Expect(secret2).NotTo(gomega.BeNil())
+
+			assertSecretEqual(secret1, baseSecret1)
+			assertSecretEqual(secret2, baseSecret2)
+
+			// update & get secret 1
+			baseSecret1.Config["prefix"] = "kv/apisix-updated"
+			_, err = s.UpdateSecret(baseSecret1)
+			gomega.Expect(err).To(gomega.BeNil())
+
+			secret, err = s.GetSecret("vault/secret1")
+			gomega.Expect(err).To(gomega.BeNil())
+			assertSecretEqual(secret, baseSecret1)
+
+			// delete secret 2
+			err = s.DeleteSecret("vault/secret2")
+			gomega.Expect(err).To(gomega.BeNil())
+
+			_, err = s.GetSecret("vault/secret2")
+			gomega.Expect(err).To(gomega.Equal(apisix.ErrNotFound))
+
+			// delete secret 1
+			err = s.DeleteSecret("vault/secret1")
+			gomega.Expect(err).To(gomega.BeNil())
+
+			_, err = s.GetSecret("vault/secret1")
+			gomega.Expect(err).To(gomega.Equal(apisix.ErrNotFound))
+
+			// final list
+			secrets, err = s.ListSecret()
+			gomega.Expect(err).To(gomega.BeNil())
+			gomega.Expect(len(secrets)).To(gomega.Equal(0))
+		})
+	})
+})
@@ -0,0 +1,112 @@
+package upstream
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/api7/adc/pkg/api/apisix"
+	"github.com/api7/adc/pkg/api/apisix/types"
+	"github.com/api7/adc/test/config"
+	"github.com/api7/adc/test/scaffold"
+)
+
+var _ = ginkgo.Describe("adc APISIX upstream SDK tests", func() {
+	ginkgo.Context("Basic functions", func() {
+		s := scaffold.NewScaffold()
+		ginkgo.It("Upstream resource", func() {
+			var (
+				err      error
+				upstream *types.Upstream
+			)
+
+			// utils
+			assertUpstreamEqual := func(expect, toBe *types.Upstream) {
+				gomega.Expect(expect.ID).To(gomega.Equal(toBe.ID))
+				gomega.Expect(expect.Name).To(gomega.Equal(toBe.Name))
+				gomega.Expect(expect.Nodes).To(gomega.Equal(toBe.Nodes))
+			}
+
+			// create upstream 1
+			baseUpstream1 := &types.Upstream{
+				ID:   "upstream1",
+				Name: "upstream1",
+				Nodes: []types.UpstreamNode{
+					{
+						Host:   config.TestUpstream,
+						Port:   80,
+						Weight: 1,
+					},
+				},
+			}
+			_, err = s.CreateUpstream(baseUpstream1)
+			gomega.Expect(err).To(gomega.BeNil(), "error while creating upstream")
+
+			// get upstream 1
+			upstream, err = s.GetUpstream("upstream1")
+			gomega.Expect(err).To(gomega.BeNil())
+			assertUpstreamEqual(upstream, baseUpstream1)
+
+			// create upstream 2
+			baseUpstream2 := &types.Upstream{
+				ID:   "upstream2",
+				Name: "upstream2",
+				Nodes: []types.UpstreamNode{
+					{
+						Host:   config.TestUpstream,
+						Port:   8080,
+						Weight: 1,
+					},
+				},
+			}
+			upstream, err = s.CreateUpstream(baseUpstream2)
+			gomega.Expect(err).To(gomega.BeNil())
+			assertUpstreamEqual(upstream, baseUpstream2)
+
+			// test list
+			upstreams, err := s.ListUpstream()
+			gomega.Expect(err).To(gomega.BeNil())
+			gomega.Expect(len(upstreams)).To(gomega.Equal(2))
+			var upstream1, upstream2 *types.Upstream
+			for _, u := range upstreams {
+				if u.ID == "upstream1" {
+					upstream1 = u
+				} else if u.ID == "upstream2" {
+					upstream2 = u
+				}
+			}
+			gomega.Expect(upstream1).NotTo(gomega.BeNil())
+			gomega.Expect(upstream2).NotTo(gomega.BeNil())
+
+			assertUpstreamEqual(upstream1, baseUpstream1)
+			assertUpstreamEqual(upstream2, baseUpstream2)
+
+			// update & get upstream 1
+			baseUpstream1.Nodes[0].Weight = 10
+			_, err = s.UpdateUpstream(baseUpstream1)
+			gomega.Expect(err).To(gomega.BeNil())
+
+			upstream, err = s.GetUpstream("upstream1")
+			gomega.Expect(err).To(gomega.BeNil())
+			assertUpstreamEqual(upstream, baseUpstream1)
+
+			// delete upstream 2
+			err = s.DeleteUpstream("upstream2")
+			gomega.Expect(err).To(gomega.BeNil())
+
+			_, err = s.GetUpstream("upstream2")
+			gomega.Expect(err).To(gomega.Equal(apisix.ErrNotFound))
+
+			// delete upstream 1
+			err = s.DeleteUpstream("upstream1")
+			gomega.Expect(err).To(gomega.BeNil())
+
+			_, err = s.GetUpstream("upstream1")
+			gomega.Expect(err).To(gomega.Equal(apisix.ErrNotFound))
+
+			// final list
+			upstreams, err = s.ListUpstream()
+			gomega.Expect(err).To(gomega.BeNil())
+			gomega.Expect(len(upstreams)).To(gomega.Equal(0))
+		})
+	})
+})
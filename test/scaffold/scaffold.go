@@ -21,12 +21,16 @@ type Scaffold struct {
 	cluster apisix.Cluster
 
 	routes          map[string]struct{}
+	streamRoutes    map[string]struct{}
 	services        map[string]struct{}
+	upstreams       map[string]struct{}
 	consumers       map[string]struct{}
 	globalRules     map[string]struct{}
 	pluginConfigs   map[string]struct{}
 	consumerGroups  map[string]struct{}
 	pluginMetadatas map[string]struct{}
+	protos          map[string]struct{}
+	secrets         map[string]struct{}
 }
 
 func NewScaffold() *Scaffold {
@@ -54,12 +58,16 @@ func newScaffold(conf cmdconfig.ClientConfig) *Scaffold {
 		cluster: cluster,
 
 		routes:          map[string]struct{}{},
+		streamRoutes:    map[string]struct{}{},
 		services:        map[string]struct{}{},
+		upstreams:       map[string]struct{}{},
 		consumers:       map[string]struct{}{},
 		globalRules:     map[string]struct{}{},
 		pluginConfigs:   map[string]struct{}{},
 		consumerGroups:  map[string]struct{}{},
 		pluginMetadatas: map[string]struct{}{},
+		protos:          map[string]struct{}{},
+		secrets:         map[string]struct{}{},
 	}
 
 	ginkgo.BeforeEach(func() {
@@ -72,9 +80,15 @@ func newScaffold(conf cmdconfig.ClientConfig) *Scaffold {
 		for route := range s.routes {
 			s.DeleteRoute(route)
 		}
+		for streamRoute := range s.streamRoutes {
+			s.DeleteStreamRoute(streamRoute)
+		}
 		for svc := range s.services {
 			s.DeleteService(svc)
 		}
+		for upstream := range s.upstreams {
+			s.DeleteUpstream(upstream)
+		}
 		for consumer := range s.consumers {
 			s.DeleteConsumer(consumer)
 		}
@@ -90,6 +104,12 @@ func newScaffold(conf cmdconfig.ClientConfig) *Scaffold {
 		for pluginMetadata := range s.pluginMetadatas {
 			s.DeletePluginMetadata(pluginMetadata)
 		}
+		for proto := range s.protos {
+			s.DeleteProto(proto)
+		}
+		for secret := range s.secrets {
+			s.DeleteSecret(secret)
+		}
 	})
 
 	return s
@@ -101,12 +121,24 @@ func (s *Scaffold) AddRoutesFinalizer(routes ...string) {
 	}
 }
 
+func (s *Scaffold) AddStreamRoutesFinalizer(streamRoutes ...string) {
+	for _, streamRoute := range streamRoutes {
+		s.streamRoutes[streamRoute] = struct{}{}
+	}
+}
+
 func (s *Scaffold) AddServicesFinalizer(services ...string) {
 	for _, service := range services {
 		s.services[service] = struct{}{}
 	}
 }
 
+func (s *Scaffold) AddUpstreamsFinalizer(upstreams ...string) {
+	for _, upstream := range upstreams {
+		s.upstreams[upstream] = struct{}{}
+	}
+}
+
 func (s *Scaffold) AddConsumersFinalizer(consumers ...string) {
 	for _, consumer := range consumers {
 		s.consumers[consumer] = struct{}{}
@@ -137,6 +169,18 @@ func (s *Scaffold) AddPluginMetadatasFinalizer(pluginsMetadatas ...string) {
 	}
 }
 
+func (s *Scaffold) AddProtosFinalizer(protos ...string) {
+	for _, proto := range protos {
+		s.protos[proto] = struct{}{}
+	}
+}
+
+func (s *Scaffold) AddSecretsFinalizer(secrets ...string) {
+	for _, secret := range secrets {
+		s.secrets[secret] = struct{}{}
+	}
+}
+
 func (s *Scaffold) Configure(conf cmdconfig.ClientConfig) error {
 	key := conf.Token
 	input := key + "\n"
@@ -192,10 +236,23 @@ func (s *Scaffold) Sync(path string) (string, error) {
 		s.AddRoutesFinalizer(route.ID)
 	}
 
+	for _, streamRoute := range conf.StreamRoutes {
+		s.AddStreamRoutesFinalizer(streamRoute.ID)
+	}
+
 	for _, service := range conf.Services {
 		s.AddServicesFinalizer(service.ID)
 	}
 
+	for _, upstream := range conf.Upstreams {
+		for j, node := range upstream.Nodes {
+			if node.Host == "HTTPBIN_PLACEHOLDER" {
+				upstream.Nodes[j].Host = config.TestUpstream
+			}
+		}
+		s.AddUpstreamsFinalizer(upstream.ID)
+	}
+
 	for _, consumer := range conf.Consumers {
 		s.AddConsumersFinalizer(consumer.Username)
 	}
@@ -216,6 +273,14 @@ func (s *Scaffold) Sync(path string) (string, error) {
 		s.AddPluginMetadatasFinalizer(pluginMetadata.ID)
 	}
 
+	for _, proto := range conf.Protos {
+		s.AddProtosFinalizer(proto.ID)
+	}
+
+	for _, secret := range conf.Secrets {
+		s.AddSecretsFinalizer(secret.ResourceKey())
+	}
+
 	tmpFile := path + ".tmp"
 	defer func() {
 		err = os.Remove(tmpFile)
@@ -224,7 +289,8 @@ func (s *Scaffold) Sync(path string) (string, error) {
 	err = common.SaveAPISIXConfiguration(tmpFile, conf)
 	gomega.Expect(err).To(gomega.BeNil(), "save temp file to "+tmpFile)
 
-	return s.Exec("sync", "-f", tmpFile)
+	// the backup message would be mixed into the sync output
+	return s.Exec("sync", "-f", tmpFile, "--backup=false")
 }
 
 func (s *Scaffold) Dump() (string, error) {
@@ -265,6 +331,12 @@ func (s *Scaffold) DeleteRoute(id string) error {
 	return s.cluster.Route().Delete(context.Background(), id)
 }
 
+func (s *Scaffold) DeleteStreamRoute(id string) error {
+	delete(s.streamRoutes, id)
+
+	return s.cluster.StreamRoute().Delete(context.Background(), id)
+}
+
 func (s *Scaffold) GetService(service string) (*types.Service, error) {
 	return s.cluster.Service().Get(context.Background(), service)
 }
@@ -291,6 +363,32 @@ func (s *Scaffold) DeleteService(id string) error {
 	return s.cluster.Service().Delete(context.Background(), id)
 }
 
+func (s *Scaffold) GetUpstream(upstream string) (*types.Upstream, error) {
+	return s.cluster.Upstream().Get(context.Background(), upstream)
+}
+
+func (s *Scaffold) ListUpstream() ([]*types.Upstream, error) {
+	return s.cluster.Upstream().List(context.Background())
+}
+
+func (s *Scaffold) CreateUpstream(upstream *types.Upstream) (*types.Upstream, error) {
+	s.upstreams[upstream.ID] = struct{}{}
+
+	return s.cluster.Upstream().Create(context.Background(), upstream)
+}
+
+func (s *Scaffold) UpdateUpstream(upstream *types.Upstream) (*types.Upstream, error) {
+	s.upstreams[upstream.ID] = struct{}{}
+
+	return s.cluster.Upstream().Update(context.Background(), upstream)
+}
+
+func (s *Scaffold) DeleteUpstream(id string) error {
+	delete(s.upstreams, id)
+
+	return s.cluster.Upstream().Delete(context.Background(), id)
+}
+
 func (s *Scaffold) GetConsumer(username string) (*types.Consumer, error) {
 	return s.cluster.Consumer().Get(context.Background(), username)
 }
@@ -420,3 +518,55 @@ func (s *Scaffold) DeletePluginMetadata(id string) error {
 
 	return s.cluster.PluginMetadata().Delete(context.Background(), id)
 }
+
+func (s *Scaffold) GetProto(id string) (*types.Proto, error) {
+	return s.cluster.Proto().Get(context.Background(), id)
+}
+
+func (s *Scaffold) ListProto() ([]*types.Proto, error) {
+	return s.cluster.Proto().List(context.Background())
+}
+
+func (s *Scaffold) CreateProto(proto *types.Proto) (*types.Proto, error) {
+	s.protos[proto.ID] = struct{}{}
+
+	return s.cluster.Proto().Create(context.Background(), proto)
+}
+
+func (s *Scaffold) UpdateProto(proto *types.Proto) (*types.Proto, error) {
+	s.protos[proto.ID] = struct{}{}
+
+	return s.cluster.Proto().Update(context.Background(), proto)
+}
+
+func (s *Scaffold) DeleteProto(id string) error {
+	delete(s.protos, id)
+
+	return s.cluster.Proto().Delete(context.Background(), id)
+}
+
+func (s *Scaffold) GetSecret(key string) (*types.Secret, error) {
+	return s.cluster.Secret().Get(context.Background(), key)
+}
+
+func (s *Scaffold) ListSecret() ([]*types.Secret, error) {
+	return s.cluster.Secret().List(context.Background())
+}
+
+func (s *Scaffold) CreateSecret(secret *types.Secret) (*types.Secret, error) {
+	s.secrets[secret.ResourceKey()] = struct{}{}
+
+	return s.cluster.Secret().Create(context.Background(), secret)
+}
+
+func (s *Scaffold) UpdateSecret(secret *types.Secret) (*types.Secret, error) {
+	s.secrets[secret.ResourceKey()] = struct{}{}
+
+	return s.cluster.Secret().Update(context.Background(), secret)
+}
+
+func (s *Scaffold) DeleteSecret(key string) error {
+	delete(s.secrets, key)
+
+	return s.cluster.Secret().Delete(context.Background(), key)
+}
@@ -0,0 +1,30 @@
+#!/bin/bash
+
+# Generates the data ADC embeds from an APISIX instance of the given version,
+# i.e. the plugin default values and the JSON schemas for offline validation, e.g.
+#
+#   make build && ./utils/generate-data.sh 3.2.2
+#
+# APISIX is run with the quickstart in docker, and removed at exit.
+
+set -e
+
+APISIX_VERSION=${1:?"usage: $0 <APISIX version, e.g. 3.2.2>"}
+MINOR_VERSION=$(echo "$APISIX_VERSION" | cut -d. -f1,2)
+ADMIN_KEY="edd1c9f034335f136f87ad84b625c8f1"
+# the configuration of ADC is written to a temporary home directory
+ADC="env HOME=$(mktemp -d) ./bin/adc"
+
+cleanup() {
+  docker rm -f apisix-quickstart etcd-quickstart >/dev/null 2>&1 || true
+  docker network rm apisix-quickstart-net >/dev/null 2>&1 || true
+}
+trap cleanup EXIT
+
+curl -sL https://run.api7.ai/apisix/quickstart | sed "s/3.4.0-debian/${APISIX_VERSION}-debian/g" | sh
+
+echo "$ADMIN_KEY" | $ADC configure --address http://127.0.0.1:9180 -f
+$ADC ping
+
+$ADC defaults generate -o "pkg/api/apisix/types/data/default_values/${MINOR_VERSION}.json"
+$ADC schemas generate -o internal/pkg/validator/schemas